@@ -13,7 +13,7 @@ func main() {
 
 	database.Migrate(db)
 
-	r := routers.Setup(db)
+	r := routers.Setup(db, cfg)
 
 	log.Println("Server running on port", cfg.Port)
 	r.Run(":" + cfg.Port)
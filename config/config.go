@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +19,23 @@ type Config struct {
 	DBName   string
 	DBPath   string // for SQLite
 	Port     string
+
+	// Proxies whose X-Forwarded-For is believed when telling clients apart,
+	// e.g. for rate limiting. Empty means the connection's address is used.
+	TrustedProxies []string
+
+	// JWT signing. HS256 signs with JWTSecret; RS256 and EdDSA sign with the
+	// PEM private key in JWTPrivateKeyFile and publish the public keys as JWKS.
+	JWTAlgorithm      string
+	JWTSecret         string
+	JWTRandomSecret   bool // development only: sign with a random secret when JWTSecret is empty
+	JWTPrivateKeyFile string
+	JWTKeyID          string            // kid of the signing key
+	JWTVerifyKeys     map[string]string // retired keys still accepted, by kid: secrets for HS256, public key files otherwise
+	JWTTTL            time.Duration     // lifetime of access tokens
+	JWTRefreshTTL     time.Duration     // lifetime of refresh tokens, renewed on every refresh
+	JWTIssuer         string
+	JWTAudience       string
 }
 
 func Load() *Config {
@@ -34,6 +54,19 @@ func Load() *Config {
 		DBName:   getEnv("DB_NAME", "xyz_football"),
 		DBPath:   getEnv("DB_PATH", "storage/xyz_football.db"),
 		Port:     getEnv("PORT", "8080"),
+
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+
+		JWTAlgorithm:      getEnv("JWT_ALGORITHM", "HS256"),
+		JWTSecret:         os.Getenv("JWT_SECRET"),
+		JWTRandomSecret:   getEnvBool("JWT_RANDOM_SECRET"),
+		JWTPrivateKeyFile: os.Getenv("JWT_PRIVATE_KEY_FILE"),
+		JWTKeyID:          getEnv("JWT_KEY_ID", "default"),
+		JWTVerifyKeys:     getEnvMap("JWT_VERIFY_KEYS"),
+		JWTTTL:            getEnvDuration("JWT_TTL", 15*time.Minute),
+		JWTRefreshTTL:     getEnvDuration("JWT_REFRESH_TTL", 30*24*time.Hour),
+		JWTIssuer:         getEnv("JWT_ISSUER", "xyz-football"),
+		JWTAudience:       getEnv("JWT_AUDIENCE", "xyz-football"),
 	}
 }
 
@@ -44,3 +77,55 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+func getEnvBool(key string) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("invalid %s %q, expected true or false", key, value)
+	}
+	return b
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Fatalf("invalid %s %q, expected a duration such as 15m or 24h", key, value)
+	}
+	return d
+}
+
+// getEnvList reads a comma separated list.
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvMap reads a comma separated list of key=value pairs.
+func getEnvMap(key string) map[string]string {
+	values := map[string]string{}
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || k == "" || v == "" {
+			log.Fatalf("invalid %s entry %q, expected kid=value", key, pair)
+		}
+		values[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return values
+}
@@ -11,13 +11,87 @@ import (
 func Migrate(db *gorm.DB) {
 	err := db.AutoMigrate(
 		&models.Admin{},
+		&models.Season{},
+		&models.Competition{},
 		&models.Team{},
 		&models.Player{},
 		&models.Match{},
 		&models.Goal{},
+		&models.Card{},
+		&models.CupTie{},
+		&models.MatchTransition{},
+		&models.LineupEntry{},
+		&models.Substitution{},
+		&models.PlayerContract{},
+		&models.Invitation{},
+		&models.SetupLock{},
+		&models.Session{},
+		&models.RefreshToken{},
 	)
 	if err != nil {
 		log.Fatalf("Migration failed: %v", err)
 	}
+
+	// Users are deleted for good now. Those soft-deleted before stay deleted,
+	// then the soft-delete column goes, before anything else touches admins
+	if db.Migrator().HasColumn(&models.Admin{}, "deleted_at") {
+		err = db.Exec(`DELETE FROM admins WHERE deleted_at IS NOT NULL`).Error
+		if err == nil {
+			err = db.Migrator().DropColumn(&models.Admin{}, "deleted_at")
+		}
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+	}
+
+	// Admins from before roles existed had full access, and keep it
+	err = db.Exec(`UPDATE admins SET role = ? WHERE role IS NULL OR role = ''`, models.RoleSuperAdmin).Error
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+
+	// Goals recorded before the credited team was stored count for the scorer's team
+	err = db.Exec(`UPDATE goals SET team_id = (SELECT players.team_id FROM players WHERE players.id = goals.player_id)
+		WHERE team_id IS NULL OR team_id = 0`).Error
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+
+	// Players registered before contracts were tracked get an open-ended one with their team
+	err = db.Exec(`INSERT INTO player_contracts (player_id, team_id, created_at, updated_at)
+		SELECT id, team_id, created_at, created_at FROM players
+		WHERE deleted_at IS NULL AND id NOT IN (SELECT player_id FROM player_contracts)`).Error
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+
+	// Teams and players saved before search keys existed get theirs now
+	var teams []models.Team
+	err = db.Where("search_text = '' OR search_text IS NULL").
+		FindInBatches(&teams, 200, func(*gorm.DB, int) error {
+			for _, team := range teams {
+				if err := db.Model(&team).UpdateColumn("search_text", team.SearchKey()).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+	var players []models.Player
+	err = db.Where("search_text = '' OR search_text IS NULL").
+		FindInBatches(&players, 200, func(*gorm.DB, int) error {
+			for _, player := range players {
+				if err := db.Model(&player).UpdateColumn("search_text", player.SearchKey()).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+
 	log.Println("✅ Database migration complete")
 }
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"xyz-football/internal/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// legacyAdmin is the admins table as it was before roles, with users
+// soft-deleted.
+type legacyAdmin struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"size:100;not null"`
+	Email     string `gorm:"size:100;unique;not null"`
+	Password  string `gorm:"size:255;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (legacyAdmin) TableName() string { return "admins" }
+
+func TestMigrateLegacyAdmins(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // every connection would get a database of its own
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&legacyAdmin{}); err != nil {
+		t.Fatal(err)
+	}
+	active := &legacyAdmin{Name: "Active", Email: "active@example.com", Password: "hash"}
+	deleted := &legacyAdmin{Name: "Deleted", Email: "deleted@example.com", Password: "hash"}
+	for _, admin := range []*legacyAdmin{active, deleted} {
+		if err := db.Create(admin).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Delete(deleted).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Running it again must not change anything
+	Migrate(db)
+	Migrate(db)
+
+	if db.Migrator().HasColumn(&models.Admin{}, "deleted_at") {
+		t.Error("admins still have a deleted_at column")
+	}
+
+	var admins []models.Admin
+	if err := db.Find(&admins).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(admins) != 1 || admins[0].ID != active.ID {
+		t.Fatalf("admins = %+v, want only %q", admins, active.Email)
+	}
+	if admins[0].Role != models.RoleSuperAdmin {
+		t.Errorf("role = %q, want %q", admins[0].Role, models.RoleSuperAdmin)
+	}
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"xyz-football/internal/models"
 	"xyz-football/internal/services"
@@ -22,12 +24,31 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
 type RegisterRequest struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=8"`
 }
 
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+type InviteRequest struct {
+	Email string      `json:"email" binding:"required,email"`
+	Role  models.Role `json:"role" binding:"required,oneof=super_admin league_admin match_official read_only"`
+}
+
+type ChangeRoleRequest struct {
+	Role models.Role `json:"role" binding:"required,oneof=super_admin league_admin match_official read_only"`
+}
+
 // Login handles admin login
 // @Summary Admin login
 // @Description Login with email and password
@@ -46,31 +67,122 @@ func (h *AdminHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, admin, err := h.service.Login(req.Email, req.Password)
+	tokens, admin, err := h.service.Login(req.Email, req.Password)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "login successful",
-		"token":   token,
-		"data":    admin,
+		"message":       "login successful",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"data":          admin,
 	})
 }
 
-// Register handles admin registration
-// @Summary Register new admin
-// @Description Register a new admin user
+// Refresh hands out a new access token
+// @Summary Refresh the access token
+// @Description Trade a refresh token for a new access token and refresh token; every refresh token works once
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param input body RefreshRequest true "Refresh token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /admin/refresh [post]
+func (h *AdminHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.service.Refresh(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "token refreshed",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+// Logout ends the current session
+// @Summary Logout
+// @Description Revoke the access token used and the refresh token of its session
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /admin/logout [post]
+func (h *AdminHandler) Logout(c *gin.Context) {
+	if err := h.service.Logout(c.GetUint("session_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "logged out successfully",
+	})
+}
+
+// LogoutAll ends every session of the current user
+// @Summary Logout everywhere
+// @Description Revoke the tokens of every session of the current user, this one included
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/logout-all [post]
+func (h *AdminHandler) LogoutAll(c *gin.Context) {
+	ended, err := h.service.LogoutAll(c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "logged out of all sessions",
+		"data":    gin.H{"sessions_ended": ended},
+	})
+}
+
+// JWKS publishes the public keys tokens are signed with
+// @Summary JSON Web Key Set
+// @Description Public keys for verifying tokens, by kid; only available with RS256 or EdDSA
+// @Tags admin
+// @Produce json
+// @Success 200 {object} utils.JWKS
+// @Failure 404 {object} map[string]string
+// @Router /.well-known/jwks.json [get]
+func (h *AdminHandler) JWKS(c *gin.Context) {
+	keys, ok := h.service.JWKS()
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "tokens are not signed with public keys"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, keys)
+}
+
+// Setup registers the first admin
+// @Summary Register the first admin
+// @Description Register the first user as super-admin; only works while there are no users
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Param input body RegisterRequest true "Admin details"
 // @Success 201 {object} map[string]string
 // @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /admin/register [post]
-func (h *AdminHandler) Register(c *gin.Context) {
+// @Failure 409 {object} map[string]string
+// @Router /admin/setup [post]
+func (h *AdminHandler) Setup(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -83,18 +195,221 @@ func (h *AdminHandler) Register(c *gin.Context) {
 		Password: req.Password,
 	}
 
-	// check if admin exist not allowed
-	if _, err := h.service.FindByEmail(req.Email); err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "admin already exists. only 1 admin is allowed to register"})
+	if err := h.service.Setup(admin); err != nil {
+		if errors.Is(err, services.ErrSetupDone) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to register admin"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "admin registered successfully",
+	})
+}
+
+// AcceptInvitation signs up an invited user
+// @Summary Accept an invitation
+// @Description Create an account with the role of the invitation
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param input body AcceptInvitationRequest true "Invitation token and account details"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /admin/invitations/accept [post]
+func (h *AdminHandler) AcceptInvitation(c *gin.Context) {
+	var req AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.service.Register(admin); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	admin, err := h.service.AcceptInvitation(req.Token, req.Name, req.Password)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "admin registered successfully",
+		"message": "account created successfully",
+		"data":    admin,
+	})
+}
+
+// ListUsers lists every user with their role
+// @Summary List users
+// @Tags users
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	admins, err := h.service.ListUsers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch users"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": admins,
+	})
+}
+
+// ChangeRole gives a user another role
+// @Summary Change the role of a user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param input body ChangeRoleRequest true "New role"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /users/{id}/role [put]
+func (h *AdminHandler) ChangeRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var req ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	admin, err := h.service.ChangeRole(uint(id), req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "role updated successfully",
+		"data":    admin,
+	})
+}
+
+// DeleteUser removes a user
+// @Summary Delete a user
+// @Tags users
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /users/{id} [delete]
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := h.service.DeleteUser(c.GetUint("user_id"), uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "user deleted successfully",
+	})
+}
+
+// EndSessions logs a user out everywhere
+// @Summary End the sessions of a user
+// @Description Revoke the tokens of every session of the user, e.g. when their account is compromised
+// @Tags users
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /users/{id}/sessions [delete]
+func (h *AdminHandler) EndSessions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	ended, err := h.service.LogoutAll(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to end sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "sessions ended successfully",
+		"data":    gin.H{"sessions_ended": ended},
+	})
+}
+
+// Invite invites someone to join with a role
+// @Summary Invite a user
+// @Description The token is only returned here; pass it on to the invitee
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body InviteRequest true "Invitee and role"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /users/invitations [post]
+func (h *AdminHandler) Invite(c *gin.Context) {
+	var req InviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invitation, token, err := h.service.Invite(c.GetUint("user_id"), req.Email, req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "invitation created successfully",
+		"data":    invitation,
+		"token":   token,
+	})
+}
+
+// ListInvitations lists the invitations that can still be accepted
+// @Summary List pending invitations
+// @Tags users
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /users/invitations [get]
+func (h *AdminHandler) ListInvitations(c *gin.Context) {
+	invitations, err := h.service.ListInvitations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch invitations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": invitations,
+	})
+}
+
+// RevokeInvitation withdraws a pending invitation
+// @Summary Revoke an invitation
+// @Tags users
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /users/invitations/{id} [delete]
+func (h *AdminHandler) RevokeInvitation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invitation ID"})
+		return
+	}
+
+	if err := h.service.RevokeInvitation(uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "invitation revoked successfully",
 	})
 }
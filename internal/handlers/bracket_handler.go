@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"xyz-football/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BracketHandler struct {
+	service services.BracketService
+}
+
+func NewBracketHandler(service services.BracketService) *BracketHandler {
+	return &BracketHandler{service: service}
+}
+
+type CreateBracketRequest struct {
+	SeasonID       *uint     `json:"season_id"`
+	TeamIDs        []uint    `json:"team_ids" binding:"required,min=2"` // seed order, best first
+	StartDate      time.Time `json:"start_date" binding:"required"`
+	IntervalDays   int       `json:"interval_days" binding:"omitempty,min=1"`
+	TwoLegged      bool      `json:"two_legged"`
+	TwoLeggedFinal bool      `json:"two_legged_final"`
+	AwayGoalsRule  bool      `json:"away_goals_rule"`
+}
+
+func (h *BracketHandler) Create(c *gin.Context) {
+	competitionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid competition ID"})
+		return
+	}
+
+	var req CreateBracketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	bracket, err := h.service.CreateBracket(uint(competitionID), services.BracketOptions{
+		SeasonID:       req.SeasonID,
+		TeamIDs:        req.TeamIDs,
+		StartDate:      req.StartDate,
+		IntervalDays:   req.IntervalDays,
+		TwoLegged:      req.TwoLegged,
+		TwoLeggedFinal: req.TwoLeggedFinal,
+		AwayGoalsRule:  req.AwayGoalsRule,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Bracket created successfully",
+		"data":    bracket,
+	})
+}
+
+func (h *BracketHandler) Get(c *gin.Context) {
+	competitionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid competition ID"})
+		return
+	}
+
+	seasonID, err := queryUintPtr(c, "season_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	bracket, err := h.service.GetBracket(uint(competitionID), seasonID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bracket not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": bracket,
+	})
+}
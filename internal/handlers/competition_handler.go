@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CompetitionHandler struct {
+	service services.CompetitionService
+}
+
+func NewCompetitionHandler(service services.CompetitionService) *CompetitionHandler {
+	return &CompetitionHandler{service: service}
+}
+
+type CreateCompetitionRequest struct {
+	Name       string                  `json:"name" binding:"required"`
+	Type       models.CompetitionType  `json:"type" binding:"omitempty,oneof=league cup friendly"`
+	Rules      *models.StandingsRules  `json:"rules" binding:"omitempty"`
+	Discipline *models.DisciplineRules `json:"discipline" binding:"omitempty"`
+}
+
+func (h *CompetitionHandler) Create(c *gin.Context) {
+	var req CreateCompetitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	competition := &models.Competition{
+		Name:       req.Name,
+		Type:       req.Type,
+		Rules:      req.Rules,
+		Discipline: req.Discipline,
+	}
+
+	if err := h.service.CreateCompetition(competition); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Competition created successfully",
+		"data":    competition,
+	})
+}
+
+// The rules are left out, they do not fit in a flat row
+var competitionsCSV = csvExport[models.Competition]{
+	filename: "competitions",
+	columns: []csvColumn[models.Competition]{
+		{"id", func(c models.Competition) string { return csvUint(c.ID) }},
+		{"name", func(c models.Competition) string { return c.Name }},
+		{"type", func(c models.Competition) string { return string(c.Type) }},
+	},
+}
+
+func (h *CompetitionHandler) List(c *gin.Context) {
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	competitions, err := h.service.GetAllCompetitions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch competitions"})
+		return
+	}
+
+	respondData(c, format, competitionsCSV, competitions, competitions)
+}
+
+func (h *CompetitionHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid competition ID"})
+		return
+	}
+
+	competition, err := h.service.GetCompetitionByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "competition not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": competition,
+	})
+}
+
+func (h *CompetitionHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid competition ID"})
+		return
+	}
+
+	var req CreateCompetitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	competition := &models.Competition{
+		ID:         uint(id),
+		Name:       req.Name,
+		Type:       req.Type,
+		Rules:      req.Rules,
+		Discipline: req.Discipline,
+	}
+
+	if err := h.service.UpdateCompetition(competition); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Competition updated successfully",
+		"data":    competition,
+	})
+}
+
+func (h *CompetitionHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid competition ID"})
+		return
+	}
+
+	if err := h.service.DeleteCompetition(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Competition deleted successfully",
+	})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"xyz-football/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DisciplineHandler struct {
+	service services.DisciplineService
+}
+
+func NewDisciplineHandler(service services.DisciplineService) *DisciplineHandler {
+	return &DisciplineHandler{service: service}
+}
+
+func (h *DisciplineHandler) GetPlayerDiscipline(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
+		return
+	}
+
+	filter, err := reportFilter(c, "team_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	discipline, err := h.service.GetPlayerDiscipline(uint(id), filter)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": discipline,
+	})
+}
+
+func (h *DisciplineHandler) GetReport(c *gin.Context) {
+	filter, err := reportFilter(c, "team_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.GetDisciplineReport(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch discipline report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"xyz-football/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportPageSize is the number of rows loaded at a time when a list is
+// streamed as CSV.
+const exportPageSize = 500
+
+const mimeCSV = "text/csv"
+
+type responseFormat string
+
+const (
+	formatJSON  responseFormat = "json"
+	formatCSV   responseFormat = "csv"
+	formatExcel responseFormat = "excel" // CSV starting with a byte order mark, so Excel reads it as UTF-8
+)
+
+func (f responseFormat) isCSV() bool {
+	return f == formatCSV || f == formatExcel
+}
+
+// negotiateFormat picks the format of a report or list from "format" or,
+// without one, from the Accept header. JSON stays the default, and the only
+// format on routes marked "json_only".
+func negotiateFormat(c *gin.Context) (responseFormat, error) {
+	if c.GetBool("json_only") {
+		if format := responseFormat(c.Query("format")); format != "" && format != formatJSON {
+			return "", errors.New("only json is available here")
+		}
+		return formatJSON, nil
+	}
+
+	switch format := responseFormat(c.Query("format")); format {
+	case formatJSON, formatCSV, formatExcel:
+		return format, nil
+	case "":
+	default:
+		return "", errors.New("invalid format, expected json, csv or excel")
+	}
+
+	if c.NegotiateFormat(gin.MIMEJSON, mimeCSV) == mimeCSV {
+		return formatCSV, nil
+	}
+	return formatJSON, nil
+}
+
+// exportOptions selects one page of a list being streamed as CSV. An export
+// covers the whole list in the requested order, whatever page was asked for.
+func exportOptions(opts repositories.ListOptions, page int) repositories.ListOptions {
+	return repositories.ListOptions{Page: page, PerPage: exportPageSize, Sort: opts.Sort}
+}
+
+// csvColumn is one column of a CSV export and how to read it from a row.
+type csvColumn[T any] struct {
+	header string
+	value  func(T) string
+}
+
+// csvExport lays out rows of T as CSV: the file name offered to the client
+// and the columns, in the order they are written. The order is part of the
+// export format, so only ever add columns at the end.
+type csvExport[T any] struct {
+	filename string
+	columns  []csvColumn[T]
+}
+
+// write sends rows that are already loaded.
+func (e csvExport[T]) write(c *gin.Context, format responseFormat, rows []T) {
+	w := e.start(c, format)
+	for _, row := range rows {
+		e.writeRow(w, row)
+	}
+	w.Flush()
+}
+
+// stream sends a list page by page, flushing each page to the client, so a
+// large list is never held in memory as a whole. fetch loads one page of
+// exportPageSize rows; loading stops at the first page that is not full. An
+// error on the first page is returned before anything is written, so the
+// caller can still answer it; later errors cut the export short.
+func (e csvExport[T]) stream(c *gin.Context, format responseFormat, fetch func(page int) ([]T, error)) error {
+	rows, err := fetch(1)
+	if err != nil {
+		return err
+	}
+
+	w := e.start(c, format)
+	for page := 1; ; page++ {
+		for _, row := range rows {
+			e.writeRow(w, row)
+		}
+		w.Flush()
+		c.Writer.Flush()
+		if len(rows) < exportPageSize {
+			return nil
+		}
+
+		if rows, err = fetch(page + 1); err != nil {
+			_ = c.Error(fmt.Errorf("export of %s stopped after page %d: %w", e.filename, page, err))
+			return nil
+		}
+	}
+}
+
+func (e csvExport[T]) start(c *gin.Context, format responseFormat) *csv.Writer {
+	c.Header("Content-Type", mimeCSV+"; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename+".csv"))
+	c.Status(http.StatusOK)
+	if format == formatExcel {
+		_, _ = c.Writer.WriteString("\ufeff")
+	}
+
+	w := csv.NewWriter(c.Writer)
+	w.UseCRLF = true
+	header := make([]string, len(e.columns))
+	for i, col := range e.columns {
+		header[i] = col.header
+	}
+	_ = w.Write(header)
+	return w
+}
+
+func (e csvExport[T]) writeRow(w *csv.Writer, row T) {
+	record := make([]string, len(e.columns))
+	for i, col := range e.columns {
+		record[i] = csvSafe(col.value(row))
+	}
+	_ = w.Write(record)
+}
+
+// csvSafe keeps spreadsheets from running a cell as a formula: text starting
+// with a formula character gets a leading quote. Numbers, such as a negative
+// goal difference, are left as they are.
+func csvSafe(cell string) string {
+	if cell == "" || !strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return cell
+	}
+	if _, err := strconv.ParseFloat(cell, 64); err == nil {
+		return cell
+	}
+	return "'" + cell
+}
+
+// Cell formatting shared by the exports; missing values are left empty.
+
+func csvInt(v int) string { return strconv.Itoa(v) }
+
+// csvOptionalInt leaves out a 0 that stands for "not known", as omitempty
+// does in JSON.
+func csvOptionalInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
+
+func csvUint(v uint) string { return strconv.FormatUint(uint64(v), 10) }
+
+func csvIntPtr(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func csvUintPtr(v *uint) string {
+	if v == nil {
+		return ""
+	}
+	return csvUint(*v)
+}
+
+func csvFloat(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// respondData answers with data as JSON or, when CSV was asked for, with rows
+// laid out by export. rows is usually data itself, or the list inside it.
+func respondData[T any](c *gin.Context, format responseFormat, export csvExport[T], rows []T, data interface{}) {
+	if format.isCSV() {
+		export.write(c, format, rows)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": data,
+	})
+}
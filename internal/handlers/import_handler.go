@@ -0,0 +1,390 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// maxImportRows caps the size of a single import.
+const maxImportRows = 5000
+
+type ImportHandler struct {
+	service services.ImportService
+}
+
+func NewImportHandler(service services.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// ImportPlayerRequest is one row of a player import. The rules are those of
+// CreatePlayerRequest, except that the team may be given by name instead.
+type ImportPlayerRequest struct {
+	TeamID   uint                  `json:"team_id"`
+	Team     string                `json:"team" binding:"required_without=TeamID"`
+	Name     string                `json:"name" binding:"required"`
+	HeightCM float64               `json:"height_cm"`
+	WeightKG float64               `json:"weight_kg"`
+	Position models.PlayerPosition `json:"position" binding:"required,oneof=striker midfielder defender goalkeeper"`
+	Number   int                   `json:"number" binding:"required,min=1,max=99"`
+}
+
+// ImportMatchRequest is one row of a fixture import. The rules are those of
+// CreateMatchRequest, except that either team may be given by name instead.
+type ImportMatchRequest struct {
+	MatchTime     time.Time `json:"match_time" binding:"required"`
+	HomeTeamID    uint      `json:"home_team_id"`
+	HomeTeam      string    `json:"home_team" binding:"required_without=HomeTeamID"`
+	AwayTeamID    uint      `json:"away_team_id"`
+	AwayTeam      string    `json:"away_team" binding:"required_without=AwayTeamID"`
+	SeasonID      *uint     `json:"season_id"`
+	CompetitionID *uint     `json:"competition_id"`
+}
+
+func (h *ImportHandler) Teams(c *gin.Context) {
+	batch, ok := readImport[CreateTeamRequest](c)
+	if !ok {
+		return
+	}
+
+	rows := make([]services.TeamImport, len(batch.rows))
+	for i, req := range batch.rows {
+		rows[i] = services.TeamImport{
+			Team: models.Team{
+				Name:        req.Name,
+				LogoURL:     req.LogoURL,
+				FoundedYear: req.FoundedYear,
+				StadiumAddr: req.StadiumAddr,
+				City:        req.City,
+			},
+			Errors: batch.errors[i],
+		}
+	}
+
+	report, err := h.service.ImportTeams(rows, batch.dryRun)
+	respondImport(c, report, err)
+}
+
+func (h *ImportHandler) Players(c *gin.Context) {
+	batch, ok := readImport[ImportPlayerRequest](c)
+	if !ok {
+		return
+	}
+
+	rows := make([]services.PlayerImport, len(batch.rows))
+	for i, req := range batch.rows {
+		rows[i] = services.PlayerImport{
+			Player: models.Player{
+				TeamID:   req.TeamID,
+				Name:     req.Name,
+				HeightCM: req.HeightCM,
+				WeightKG: req.WeightKG,
+				Position: req.Position,
+				Number:   req.Number,
+			},
+			Team:   req.Team,
+			Errors: batch.errors[i],
+		}
+	}
+
+	report, err := h.service.ImportPlayers(rows, batch.dryRun)
+	respondImport(c, report, err)
+}
+
+func (h *ImportHandler) Matches(c *gin.Context) {
+	batch, ok := readImport[ImportMatchRequest](c)
+	if !ok {
+		return
+	}
+
+	rows := make([]services.MatchImport, len(batch.rows))
+	for i, req := range batch.rows {
+		rows[i] = services.MatchImport{
+			Match: models.Match{
+				MatchTime:     req.MatchTime,
+				HomeTeamID:    req.HomeTeamID,
+				AwayTeamID:    req.AwayTeamID,
+				SeasonID:      req.SeasonID,
+				CompetitionID: req.CompetitionID,
+			},
+			HomeTeam: req.HomeTeam,
+			AwayTeam: req.AwayTeam,
+			Errors:   batch.errors[i],
+		}
+	}
+
+	report, err := h.service.ImportMatches(rows, batch.dryRun)
+	respondImport(c, report, err)
+}
+
+// importBatch holds the rows of an import request and, at the same index,
+// the problems found while reading each row.
+type importBatch[T any] struct {
+	rows   []T
+	errors []services.ValidationErrors
+	dryRun bool
+}
+
+// readImport reads the rows of an import from a JSON array or, with a
+// text/csv body, from CSV with a header row naming the same fields. Every
+// row is checked against the binding rules of T; a broken row is reported
+// with its problems instead of failing the whole request. It writes the
+// error response itself when the request as a whole cannot be read.
+func readImport[T any](c *gin.Context) (importBatch[T], bool) {
+	var batch importBatch[T]
+
+	if raw := c.Query("dry_run"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dry_run, expected true or false"})
+			return batch, false
+		}
+		batch.dryRun = dryRun
+	}
+
+	var err error
+	switch c.ContentType() {
+	case "text/csv":
+		batch.rows, batch.errors, err = decodeCSVRows[T](c.Request.Body)
+	case "application/json", "":
+		batch.rows, batch.errors, err = decodeJSONRows[T](c.Request.Body)
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "expected a JSON or CSV (text/csv) body"})
+		return batch, false
+	}
+	if err == nil && len(batch.rows) == 0 {
+		err = errors.New("nothing to import")
+	}
+	if err == nil && len(batch.rows) > maxImportRows {
+		err = fmt.Errorf("too many rows, at most %d can be imported at once", maxImportRows)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return batch, false
+	}
+
+	for i := range batch.rows {
+		err := binding.Validator.ValidateStruct(&batch.rows[i])
+		if err == nil {
+			continue
+		}
+		// A field that could not be read is already reported and would
+		// only show up again as missing
+		unread := make(map[string]bool, len(batch.errors[i]))
+		for _, fe := range batch.errors[i] {
+			unread[fe.Field] = true
+		}
+		for _, fe := range bindingErrors(err, reflect.TypeOf(batch.rows[i])) {
+			if !unread[fe.Field] {
+				batch.errors[i] = append(batch.errors[i], fe)
+			}
+		}
+	}
+	return batch, true
+}
+
+func respondImport(c *gin.Context, report *services.ImportReport, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidImport):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   err.Error(),
+			"details": report.Errors,
+		})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import rows"})
+	case report.DryRun:
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Import is valid, nothing was saved",
+			"data":    report,
+		})
+	default:
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Import completed successfully",
+			"data":    report,
+		})
+	}
+}
+
+func decodeJSONRows[T any](body io.Reader) ([]T, []services.ValidationErrors, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(body).Decode(&raw); err != nil {
+		return nil, nil, errors.New("invalid body, expected a JSON array of rows")
+	}
+
+	rows := make([]T, len(raw))
+	errs := make([]services.ValidationErrors, len(raw))
+	for i, item := range raw {
+		if err := json.Unmarshal(item, &rows[i]); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) && typeErr.Field != "" {
+				errs[i] = append(errs[i], services.FieldError{Field: typeErr.Field, Message: "has the wrong type"})
+			} else {
+				errs[i] = append(errs[i], services.FieldError{Field: "row", Message: err.Error()})
+			}
+		}
+	}
+	return rows, errs, nil
+}
+
+func decodeCSVRows[T any](body io.Reader) ([]T, []services.ValidationErrors, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, errors.New("invalid CSV, expected a header row")
+	}
+
+	// Map every column to the field of T with the same JSON name
+	rowType := reflect.TypeOf((*T)(nil)).Elem()
+	columns := make([]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		header[i] = name
+		columns[i] = -1
+		for f := 0; f < rowType.NumField(); f++ {
+			if jsonName(rowType.Field(f)) == name {
+				columns[i] = f
+			}
+		}
+		if columns[i] < 0 {
+			return nil, nil, fmt.Errorf("invalid CSV, unknown column %q", name)
+		}
+	}
+
+	var rows []T
+	var errs []services.ValidationErrors
+	for len(rows) <= maxImportRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+
+		var row T
+		var rowErrs services.ValidationErrors
+		value := reflect.ValueOf(&row).Elem()
+		for i, cell := range record {
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				continue
+			}
+			if err := setField(value.Field(columns[i]), cell); err != nil {
+				rowErrs = append(rowErrs, services.FieldError{Field: header[i], Message: err.Error()})
+			}
+		}
+		rows = append(rows, row)
+		errs = append(errs, rowErrs)
+	}
+	return rows, errs, nil
+}
+
+// setField parses a CSV cell into a field of one of the types used by the
+// import requests.
+func setField(field reflect.Value, cell string) error {
+	if field.Type() == reflect.TypeOf(time.Time{}) {
+		t, err := time.Parse(time.RFC3339, cell)
+		if err != nil {
+			return errors.New("must be an RFC 3339 timestamp")
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	// An optional field stays unset unless its cell can be read
+	if field.Kind() == reflect.Ptr {
+		value := reflect.New(field.Type().Elem())
+		if err := setField(value.Elem(), cell); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(cell)
+	case reflect.Int:
+		v, err := strconv.ParseInt(cell, 10, 0)
+		if err != nil {
+			return errors.New("must be a whole number")
+		}
+		field.SetInt(v)
+	case reflect.Uint:
+		v, err := strconv.ParseUint(cell, 10, 32)
+		if err != nil {
+			return errors.New("must be a positive whole number")
+		}
+		field.SetUint(v)
+	case reflect.Float64:
+		v, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		field.SetFloat(v)
+	default:
+		return errors.New("is not supported in CSV")
+	}
+	return nil
+}
+
+// bindingErrors turns the binding rules a row breaks into field errors named
+// after its JSON fields.
+func bindingErrors(err error, rowType reflect.Type) services.ValidationErrors {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return services.ValidationErrors{{Field: "row", Message: err.Error()}}
+	}
+
+	fieldName := func(name string) string {
+		if f, ok := rowType.FieldByName(name); ok {
+			return jsonName(f)
+		}
+		return name
+	}
+
+	errs := make(services.ValidationErrors, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		var message string
+		switch fe.Tag() {
+		case "required":
+			message = "is required"
+		case "required_without":
+			message = "is required unless " + fieldName(fe.Param()) + " is given"
+		case "oneof":
+			message = "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+		case "min":
+			message = "must be at least " + fe.Param()
+		case "max":
+			message = "must be at most " + fe.Param()
+		default:
+			message = "failed the " + fe.Tag() + " rule"
+		}
+		errs = append(errs, services.FieldError{Field: fieldName(fe.StructField()), Message: message})
+	}
+	return errs
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
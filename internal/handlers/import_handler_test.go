@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"xyz-football/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeImportService records the rows the handler passed on and accepts them
+// unless one already carries an error.
+type fakeImportService struct {
+	players []services.PlayerImport
+	dryRun  bool
+}
+
+func (f *fakeImportService) ImportTeams(rows []services.TeamImport, dryRun bool) (*services.ImportReport, error) {
+	return &services.ImportReport{DryRun: dryRun, Total: len(rows)}, nil
+}
+
+func (f *fakeImportService) ImportPlayers(rows []services.PlayerImport, dryRun bool) (*services.ImportReport, error) {
+	f.players, f.dryRun = rows, dryRun
+	report := &services.ImportReport{DryRun: dryRun, Total: len(rows)}
+	for i, row := range rows {
+		if len(row.Errors) > 0 {
+			report.Errors = append(report.Errors, services.RowError{Row: i + 1, Errors: row.Errors})
+		}
+	}
+	if len(report.Errors) > 0 {
+		return report, services.ErrInvalidImport
+	}
+	if !dryRun {
+		report.Created = len(rows)
+	}
+	return report, nil
+}
+
+func (f *fakeImportService) ImportMatches(rows []services.MatchImport, dryRun bool) (*services.ImportReport, error) {
+	return &services.ImportReport{DryRun: dryRun, Total: len(rows)}, nil
+}
+
+// fieldErrors lists the problems of every row as "row field: message".
+func fieldErrors(rows []services.PlayerImport) []string {
+	var got []string
+	for i, row := range rows {
+		for _, fe := range row.Errors {
+			got = append(got, fmt.Sprintf("%d %s: %s", i+1, fe.Field, fe.Message))
+		}
+	}
+	return got
+}
+
+func TestImportHandlerPlayers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		query       string
+		contentType string
+		body        string
+		wantStatus  int
+		wantErrors  []string
+		wantDryRun  bool
+	}{
+		{
+			name:        "CSV",
+			contentType: "text/csv",
+			body:        "team,name,position,number\nAlpha,Ann,striker,9\nBeta,Bob,defender,4\n",
+			wantStatus:  http.StatusCreated,
+		},
+		{
+			name:        "CSV with a byte order mark and loose header",
+			contentType: "text/csv; charset=utf-8",
+			body:        "\ufeffTeam , Name,POSITION,number\nAlpha,Ann,striker,9\n",
+			wantStatus:  http.StatusCreated,
+		},
+		{
+			name:        "CSV unknown column",
+			contentType: "text/csv",
+			body:        "team,name,position,number,shoe_size\nAlpha,Ann,striker,9,42\n",
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "CSV cell of the wrong type",
+			contentType: "text/csv",
+			body:        "team,name,position,number\nAlpha,Ann,striker,nine\n",
+			wantStatus:  http.StatusUnprocessableEntity,
+			wantErrors:  []string{"1 number: must be a whole number"},
+		},
+		{
+			name:        "CSV breaking the binding rules",
+			contentType: "text/csv",
+			body:        "team_id,name,position,number\n,Ann,winger,100\n",
+			wantStatus:  http.StatusUnprocessableEntity,
+			wantErrors: []string{
+				"1 team: is required unless team_id is given",
+				"1 position: must be one of: striker, midfielder, defender, goalkeeper",
+				"1 number: must be at most 99",
+			},
+		},
+		{
+			name:        "JSON",
+			contentType: "application/json",
+			body:        `[{"team_id": 1, "name": "Ann", "position": "striker", "number": 9}]`,
+			wantStatus:  http.StatusCreated,
+		},
+		{
+			name:        "JSON field of the wrong type",
+			contentType: "application/json",
+			body:        `[{"team": "Alpha", "name": "Ann", "position": "striker", "number": "9"}]`,
+			wantStatus:  http.StatusUnprocessableEntity,
+			wantErrors:  []string{"1 number: has the wrong type"},
+		},
+		{
+			name:        "JSON that is not an array",
+			contentType: "application/json",
+			body:        `{"team": "Alpha"}`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "no rows",
+			contentType: "application/json",
+			body:        `[]`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "dry run",
+			query:       "?dry_run=true",
+			contentType: "text/csv",
+			body:        "team,name,position,number\nAlpha,Ann,striker,9\n",
+			wantStatus:  http.StatusOK,
+			wantDryRun:  true,
+		},
+		{
+			name:        "invalid dry run",
+			query:       "?dry_run=maybe",
+			contentType: "text/csv",
+			body:        "team,name,position,number\nAlpha,Ann,striker,9\n",
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "unsupported body",
+			contentType: "application/xml",
+			body:        "<players/>",
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeImportService{}
+			r := gin.New()
+			r.POST("/import/players", NewImportHandler(service).Players)
+
+			req := httptest.NewRequest(http.MethodPost, "/import/players"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if got := fieldErrors(service.players); !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("errors = %q, want %q", got, tt.wantErrors)
+			}
+			if service.dryRun != tt.wantDryRun {
+				t.Errorf("dry run = %v, want %v", service.dryRun, tt.wantDryRun)
+			}
+		})
+	}
+}
+
+func TestDecodeCSVRows(t *testing.T) {
+	body := "match_time,home_team,away_team_id,season_id\n" +
+		"2025-08-01T15:00:00Z,Alpha,2,\n" +
+		"2025-08-08T15:00:00Z,Beta,1,3\n"
+
+	rows, errs, err := decodeCSVRows[ImportMatchRequest](strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("decodeCSVRows() error = %v", err)
+	}
+	if len(rows) != 2 || len(errs) != 2 {
+		t.Fatalf("got %d rows and %d error lists, want 2 of each", len(rows), len(errs))
+	}
+	if errs[0] != nil || errs[1] != nil {
+		t.Errorf("errors = %v, want none", errs)
+	}
+	if rows[0].HomeTeam != "Alpha" || rows[0].AwayTeamID != 2 || rows[0].MatchTime.Day() != 1 {
+		t.Errorf("row 1 = %+v", rows[0])
+	}
+	if rows[0].SeasonID != nil {
+		t.Errorf("empty season_id read as %d, want none", *rows[0].SeasonID)
+	}
+	if rows[1].SeasonID == nil || *rows[1].SeasonID != 3 {
+		t.Errorf("row 2 season_id = %v, want 3", rows[1].SeasonID)
+	}
+}
+
+func TestSetField(t *testing.T) {
+	var row struct {
+		Name     string
+		Number   int
+		TeamID   uint
+		Height   float64
+		SeasonID *uint
+		Tags     []string
+	}
+	value := reflect.ValueOf(&row).Elem()
+
+	tests := []struct {
+		field   string
+		cell    string
+		wantErr bool
+	}{
+		{"Name", "Ann", false},
+		{"Number", "7", false},
+		{"Number", "7.5", true},
+		{"TeamID", "3", false},
+		{"TeamID", "-3", true},
+		{"Height", "180.5", false},
+		{"Height", "tall", true},
+		{"SeasonID", "2", false},
+		{"SeasonID", "two", true},
+		{"Tags", "a", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field+"="+tt.cell, func(t *testing.T) {
+			err := setField(value.FieldByName(tt.field), tt.cell)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("setField() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if row.Name != "Ann" || row.Number != 7 || row.TeamID != 3 || row.Height != 180.5 || row.SeasonID == nil || *row.SeasonID != 2 {
+		t.Errorf("row = %+v", row)
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LineupHandler struct {
+	service services.LineupService
+}
+
+func NewLineupHandler(service services.LineupService) *LineupHandler {
+	return &LineupHandler{service: service}
+}
+
+type SubmitLineupRequest struct {
+	TeamID   uint                 `json:"team_id" binding:"required"`
+	Starters []LineupEntryRequest `json:"starters" binding:"required,dive"`
+	Bench    []LineupEntryRequest `json:"bench" binding:"dive"`
+}
+
+type LineupEntryRequest struct {
+	PlayerID    uint                  `json:"player_id" binding:"required"`
+	ShirtNumber int                   `json:"shirt_number" binding:"omitempty,min=1,max=99"`
+	Position    models.PlayerPosition `json:"position" binding:"omitempty,oneof=striker midfielder defender goalkeeper"`
+}
+
+type SubstitutionRequest struct {
+	TeamID      uint `json:"team_id" binding:"required"`
+	PlayerOffID uint `json:"player_off_id" binding:"required"`
+	PlayerOnID  uint `json:"player_on_id" binding:"required"`
+	Minute      int  `json:"minute" binding:"required,min=1,max=120"`
+	AddedTime   int  `json:"added_time" binding:"min=0,max=30"`
+}
+
+func (h *LineupHandler) Submit(c *gin.Context) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match ID"})
+		return
+	}
+
+	var req SubmitLineupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lineups, err := h.service.SubmitLineup(uint(matchID), services.TeamLineup{
+		TeamID:   req.TeamID,
+		Starters: lineupEntries(req.Starters),
+		Bench:    lineupEntries(req.Bench),
+	})
+	if err != nil {
+		var validationErrs services.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid lineup",
+				"details": validationErrs,
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Lineup submitted successfully",
+		"data":    lineups,
+	})
+}
+
+func lineupEntries(req []LineupEntryRequest) []models.LineupEntry {
+	entries := make([]models.LineupEntry, len(req))
+	for i, e := range req {
+		entries[i] = models.LineupEntry{
+			PlayerID:    e.PlayerID,
+			ShirtNumber: e.ShirtNumber,
+			Position:    e.Position,
+		}
+	}
+	return entries
+}
+
+func (h *LineupHandler) Get(c *gin.Context) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match ID"})
+		return
+	}
+
+	lineups, err := h.service.GetLineups(uint(matchID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": lineups,
+	})
+}
+
+func (h *LineupHandler) AddSubstitution(c *gin.Context) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match ID"})
+		return
+	}
+
+	var req SubstitutionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sub := &models.Substitution{
+		TeamID:      req.TeamID,
+		PlayerOffID: req.PlayerOffID,
+		PlayerOnID:  req.PlayerOnID,
+		Minute:      req.Minute,
+		AddedTime:   req.AddedTime,
+	}
+
+	if err := h.service.AddSubstitution(uint(matchID), sub); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Substitution recorded successfully",
+		"data":    sub,
+	})
+}
+
+func (h *LineupHandler) DeleteSubstitution(c *gin.Context) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match ID"})
+		return
+	}
+
+	subID, err := strconv.ParseUint(c.Param("subId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid substitution ID"})
+		return
+	}
+
+	if err := h.service.DeleteSubstitution(uint(matchID), uint(subID)); err != nil {
+		if errors.Is(err, services.ErrSubstitutionInUse) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Substitution deleted successfully",
+	})
+}
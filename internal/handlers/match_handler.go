@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
 
 	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
 	"xyz-football/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -20,20 +23,88 @@ func NewMatchHandler(service services.MatchService) *MatchHandler {
 }
 
 type CreateMatchRequest struct {
-	MatchTime  time.Time `json:"match_time" binding:"required"`
-	HomeTeamID uint      `json:"home_team_id" binding:"required"`
-	AwayTeamID uint      `json:"away_team_id" binding:"required,nefield=HomeTeamID"`
+	MatchTime     time.Time `json:"match_time" binding:"required"`
+	HomeTeamID    uint      `json:"home_team_id" binding:"required"`
+	AwayTeamID    uint      `json:"away_team_id" binding:"required,nefield=HomeTeamID"`
+	SeasonID      *uint     `json:"season_id"`
+	CompetitionID *uint     `json:"competition_id"`
 }
 
 type ReportGoalRequest struct {
-	PlayerID uint `json:"player_id" binding:"required"`
-	Minute   int  `json:"minute" binding:"required,min=1,max=130"`
+	PlayerID  uint            `json:"player_id" binding:"required"`
+	TeamID    uint            `json:"team_id"`
+	Type      models.GoalType `json:"type" binding:"omitempty,oneof=open_play penalty own_goal free_kick header"`
+	Minute    int             `json:"minute" binding:"required,min=1,max=130"`
+	AddedTime int             `json:"added_time" binding:"min=0,max=30"`
+
+	AssistPlayerID       *uint `json:"assist_player_id"`
+	SecondAssistPlayerID *uint `json:"second_assist_player_id"`
 }
 
 type ReportResultRequest struct {
-	HomeScore int                 `json:"home_score"`
-	AwayScore int                 `json:"away_score"`
-	Goals     []ReportGoalRequest `json:"goals"`
+	HomeScore          int                 `json:"home_score" binding:"min=0"`
+	AwayScore          int                 `json:"away_score" binding:"min=0"`
+	HomeExtraTimeScore *int                `json:"home_extra_time_score" binding:"omitempty,min=0"`
+	AwayExtraTimeScore *int                `json:"away_extra_time_score" binding:"omitempty,min=0"`
+	HomePenalties      *int                `json:"home_penalties" binding:"omitempty,min=0"`
+	AwayPenalties      *int                `json:"away_penalties" binding:"omitempty,min=0"`
+	Goals              []ReportGoalRequest `json:"goals" binding:"dive"`
+	Cards              []ReportCardRequest `json:"cards" binding:"dive"`
+}
+
+type ReportCardRequest struct {
+	PlayerID  uint            `json:"player_id" binding:"required"`
+	Type      models.CardType `json:"type" binding:"required,oneof=yellow second_yellow red"`
+	Minute    int             `json:"minute" binding:"required,min=1,max=130"`
+	AddedTime int             `json:"added_time" binding:"min=0,max=30"`
+	Reason    string          `json:"reason"`
+}
+
+type StatusChangeRequest struct {
+	Reason string `json:"reason"`
+}
+
+type RescheduleMatchRequest struct {
+	MatchTime time.Time `json:"match_time" binding:"required"`
+	Reason    string    `json:"reason"`
+}
+
+type AwardMatchRequest struct {
+	WinnerTeamID uint   `json:"winner_team_id" binding:"required"`
+	HomeScore    *int   `json:"home_score" binding:"omitempty,min=0"`
+	AwayScore    *int   `json:"away_score" binding:"omitempty,min=0"`
+	Reason       string `json:"reason" binding:"required"`
+}
+
+type GenerateFixturesRequest struct {
+	TeamIDs          []uint    `json:"team_ids" binding:"required,min=2"`
+	StartDate        time.Time `json:"start_date" binding:"required"`
+	IntervalDays     int       `json:"interval_days" binding:"omitempty,min=1"`
+	DoubleRoundRobin bool      `json:"double_round_robin"`
+	CompetitionID    *uint     `json:"competition_id"`
+	DryRun           bool      `json:"dry_run"`
+}
+
+var matchesCSV = csvExport[models.Match]{
+	filename: "matches",
+	columns: []csvColumn[models.Match]{
+		{"id", func(m models.Match) string { return csvUint(m.ID) }},
+		{"match_time", func(m models.Match) string { return csvTime(m.MatchTime) }},
+		{"status", func(m models.Match) string { return string(m.Status) }},
+		{"home_team_id", func(m models.Match) string { return csvUint(m.HomeTeamID) }},
+		{"home_team", func(m models.Match) string { return m.HomeTeam.Name }},
+		{"away_team_id", func(m models.Match) string { return csvUint(m.AwayTeamID) }},
+		{"away_team", func(m models.Match) string { return m.AwayTeam.Name }},
+		{"home_score", func(m models.Match) string { return csvIntPtr(m.HomeScore) }},
+		{"away_score", func(m models.Match) string { return csvIntPtr(m.AwayScore) }},
+		{"home_extra_time_score", func(m models.Match) string { return csvIntPtr(m.HomeExtraTimeScore) }},
+		{"away_extra_time_score", func(m models.Match) string { return csvIntPtr(m.AwayExtraTimeScore) }},
+		{"home_penalties", func(m models.Match) string { return csvIntPtr(m.HomePenalties) }},
+		{"away_penalties", func(m models.Match) string { return csvIntPtr(m.AwayPenalties) }},
+		{"season_id", func(m models.Match) string { return csvUintPtr(m.SeasonID) }},
+		{"competition_id", func(m models.Match) string { return csvUintPtr(m.CompetitionID) }},
+		{"matchday", func(m models.Match) string { return csvOptionalInt(m.Matchday) }},
+	},
 }
 
 func (h *MatchHandler) Create(c *gin.Context) {
@@ -44,13 +115,23 @@ func (h *MatchHandler) Create(c *gin.Context) {
 	}
 
 	match := &models.Match{
-		MatchTime:  req.MatchTime,
-		HomeTeamID: req.HomeTeamID,
-		AwayTeamID: req.AwayTeamID,
-		Status:     models.Scheduled,
+		MatchTime:     req.MatchTime,
+		HomeTeamID:    req.HomeTeamID,
+		AwayTeamID:    req.AwayTeamID,
+		SeasonID:      req.SeasonID,
+		CompetitionID: req.CompetitionID,
+		Status:        models.Scheduled,
 	}
 
 	if err := h.service.CreateMatch(match); err != nil {
+		var validationErrs services.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid match",
+				"details": validationErrs,
+			})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -62,40 +143,77 @@ func (h *MatchHandler) Create(c *gin.Context) {
 }
 
 func (h *MatchHandler) List(c *gin.Context) {
-	// Check for date range query params
-	startDate := c.Query("start_date")
-	endDate := c.Query("end_date")
-
-	if startDate != "" && endDate != "" {
-		start, err1 := time.Parse(time.RFC3339, startDate)
-		end, err2 := time.Parse(time.RFC3339, endDate)
+	opts, err := listOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-		if err1 != nil || err2 != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, use RFC3339"})
-			return
-		}
+	filter, err := matchListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-		matches, err := h.service.GetMatchesByDateRange(start, end)
+	if format.isCSV() {
+		err := matchesCSV.stream(c, format, func(page int) ([]models.Match, error) {
+			matches, _, err := h.service.ListMatches(filter, exportOptions(opts, page))
+			return matches, err
+		})
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch matches"})
-			return
+			listError(c, err, "failed to fetch matches")
 		}
-		c.JSON(http.StatusOK, gin.H{
-			"data": matches,
-		})
 		return
 	}
 
-	// If no date range, get all matches
-	matches, err := h.service.GetAllMatches()
+	matches, total, err := h.service.ListMatches(filter, opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch matches"})
+		listError(c, err, "failed to fetch matches")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": matches,
-	})
+	respondList(c, matches, total, opts)
+}
+
+// matchListFilter reads the filters of the match list from the query string.
+func matchListFilter(c *gin.Context) (repositories.MatchListFilter, error) {
+	var filter repositories.MatchListFilter
+	var err error
+	if filter.Status, err = queryMatchStatus(c); err != nil {
+		return filter, err
+	}
+	if filter.SeasonID, err = queryUintPtr(c, "season_id"); err != nil {
+		return filter, err
+	}
+	if filter.CompetitionID, err = queryUintPtr(c, "competition_id"); err != nil {
+		return filter, err
+	}
+	if filter.TeamID, err = queryUintPtr(c, "team_id"); err != nil {
+		return filter, err
+	}
+
+	// Date range, either bound may be left open
+	if raw := c.Query("start_date"); raw != "" {
+		start, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, errors.New("invalid date format, use RFC3339")
+		}
+		filter.From = &start
+	}
+	if raw := c.Query("end_date"); raw != "" {
+		end, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, errors.New("invalid date format, use RFC3339")
+		}
+		filter.To = &end
+	}
+
+	return filter, nil
 }
 
 func (h *MatchHandler) Get(c *gin.Context) {
@@ -130,13 +248,23 @@ func (h *MatchHandler) Update(c *gin.Context) {
 	}
 
 	match := &models.Match{
-		ID:         uint(id),
-		MatchTime:  req.MatchTime,
-		HomeTeamID: req.HomeTeamID,
-		AwayTeamID: req.AwayTeamID,
+		ID:            uint(id),
+		MatchTime:     req.MatchTime,
+		HomeTeamID:    req.HomeTeamID,
+		AwayTeamID:    req.AwayTeamID,
+		SeasonID:      req.SeasonID,
+		CompetitionID: req.CompetitionID,
 	}
 
 	if err := h.service.UpdateMatch(match); err != nil {
+		var validationErrs services.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid match",
+				"details": validationErrs,
+			})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -179,12 +307,49 @@ func (h *MatchHandler) ReportResult(c *gin.Context) {
 	goals := make([]models.Goal, len(req.Goals))
 	for i, g := range req.Goals {
 		goals[i] = models.Goal{
-			PlayerID: g.PlayerID,
-			Minute:   g.Minute,
+			PlayerID:  g.PlayerID,
+			TeamID:    g.TeamID,
+			Type:      g.Type,
+			Minute:    g.Minute,
+			AddedTime: g.AddedTime,
+
+			AssistPlayerID:       g.AssistPlayerID,
+			SecondAssistPlayerID: g.SecondAssistPlayerID,
 		}
 	}
 
-	if err := h.service.ReportMatchResult(uint(matchID), req.HomeScore, req.AwayScore, goals); err != nil {
+	cards := make([]models.Card, len(req.Cards))
+	for i, card := range req.Cards {
+		cards[i] = models.Card{
+			PlayerID:  card.PlayerID,
+			Type:      card.Type,
+			Minute:    card.Minute,
+			AddedTime: card.AddedTime,
+			Reason:    card.Reason,
+		}
+	}
+
+	result := services.MatchResult{
+		HomeScore:          req.HomeScore,
+		AwayScore:          req.AwayScore,
+		HomeExtraTimeScore: req.HomeExtraTimeScore,
+		AwayExtraTimeScore: req.AwayExtraTimeScore,
+		HomePenalties:      req.HomePenalties,
+		AwayPenalties:      req.AwayPenalties,
+		Goals:              goals,
+		Cards:              cards,
+		ReportedBy:         c.GetUint("user_id"),
+	}
+
+	if err := h.service.ReportMatchResult(uint(matchID), result); err != nil {
+		var validationErrs services.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid match result",
+				"details": validationErrs,
+			})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -192,12 +357,138 @@ func (h *MatchHandler) ReportResult(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Match result reported successfully"})
 }
 
+func (h *MatchHandler) Kickoff(c *gin.Context) {
+	h.changeStatus(c, models.Live, models.Scheduled, models.Postponed)
+}
+func (h *MatchHandler) HalfTime(c *gin.Context) { h.changeStatus(c, models.HalfTime) }
+func (h *MatchHandler) Resume(c *gin.Context)   { h.changeStatus(c, models.Live, models.HalfTime) }
+func (h *MatchHandler) Postpone(c *gin.Context) { h.changeStatus(c, models.Postponed) }
+func (h *MatchHandler) Cancel(c *gin.Context)   { h.changeStatus(c, models.Cancelled) }
+func (h *MatchHandler) Abandon(c *gin.Context)  { h.changeStatus(c, models.Abandoned) }
+
+// changeStatus handles the transitions that only take an optional reason.
+// When from is given the match must currently be in one of those states.
+func (h *MatchHandler) changeStatus(c *gin.Context, status models.MatchStatus, from ...models.MatchStatus) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match ID"})
+		return
+	}
+
+	var req StatusChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	match, err := h.service.ChangeStatus(uint(matchID), services.StatusChange{
+		Status:  status,
+		From:    from,
+		AdminID: c.GetUint("user_id"),
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Match status changed to " + string(status),
+		"data":    match,
+	})
+}
+
+func (h *MatchHandler) Reschedule(c *gin.Context) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match ID"})
+		return
+	}
+
+	var req RescheduleMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	match, err := h.service.ChangeStatus(uint(matchID), services.StatusChange{
+		Status:    models.Scheduled,
+		AdminID:   c.GetUint("user_id"),
+		Reason:    req.Reason,
+		MatchTime: &req.MatchTime,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Match rescheduled successfully",
+		"data":    match,
+	})
+}
+
+func (h *MatchHandler) Award(c *gin.Context) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match ID"})
+		return
+	}
+
+	var req AwardMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	match, err := h.service.ChangeStatus(uint(matchID), services.StatusChange{
+		Status:       models.Awarded,
+		AdminID:      c.GetUint("user_id"),
+		Reason:       req.Reason,
+		WinnerTeamID: &req.WinnerTeamID,
+		HomeScore:    req.HomeScore,
+		AwayScore:    req.AwayScore,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Match awarded successfully",
+		"data":    match,
+	})
+}
+
+func (h *MatchHandler) History(c *gin.Context) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match ID"})
+		return
+	}
+
+	history, err := h.service.GetMatchHistory(uint(matchID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": history,
+	})
+}
+
 func (h *MatchHandler) GetByTeam(c *gin.Context) {
 	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team ID"})
 		return
 	}
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	matches, err := h.service.GetMatchesByTeam(uint(teamID))
 	if err != nil {
@@ -205,7 +496,45 @@ func (h *MatchHandler) GetByTeam(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": matches,
+	respondData(c, format, matchesCSV, matches, matches)
+}
+
+func (h *MatchHandler) GenerateFixtures(c *gin.Context) {
+	seasonID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid season ID"})
+		return
+	}
+
+	var req GenerateFixturesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	fixtures, err := h.service.GenerateFixtures(uint(seasonID), services.FixtureOptions{
+		TeamIDs:          req.TeamIDs,
+		StartDate:        req.StartDate,
+		IntervalDays:     req.IntervalDays,
+		DoubleRoundRobin: req.DoubleRoundRobin,
+		CompetitionID:    req.CompetitionID,
+		DryRun:           req.DryRun,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.DryRun {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Fixtures preview generated",
+			"data":    fixtures,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Fixtures generated successfully",
+		"data":    fixtures,
 	})
 }
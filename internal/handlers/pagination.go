@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"xyz-football/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+// listOptions reads page, per_page and sort from the query string.
+func listOptions(c *gin.Context) (repositories.ListOptions, error) {
+	opts := repositories.ListOptions{Page: 1, PerPage: defaultPerPage, Sort: c.Query("sort")}
+
+	if raw := c.Query("page"); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			return opts, errors.New("invalid page, expected a positive number")
+		}
+		opts.Page = page
+	}
+	if raw := c.Query("per_page"); raw != "" {
+		perPage, err := strconv.Atoi(raw)
+		if err != nil || perPage < 1 || perPage > maxPerPage {
+			return opts, errors.New("invalid per_page, expected a number from 1 to 100")
+		}
+		opts.PerPage = perPage
+	}
+
+	return opts, nil
+}
+
+// respondList writes one page of a list with the paging metadata and links to
+// the neighbouring pages, which keep every other query parameter.
+func respondList(c *gin.Context, data interface{}, total int64, opts repositories.ListOptions) {
+	totalPages := int((total + int64(opts.PerPage) - 1) / int64(opts.PerPage))
+
+	links := gin.H{"self": pageLink(c, opts.Page)}
+	if opts.Page < totalPages {
+		links["next"] = pageLink(c, opts.Page+1)
+	}
+	if opts.Page > 1 {
+		links["prev"] = pageLink(c, min(opts.Page-1, max(totalPages, 1)))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": data,
+		"meta": gin.H{
+			"page":        opts.Page,
+			"per_page":    opts.PerPage,
+			"total":       total,
+			"total_pages": totalPages,
+		},
+		"links": links,
+	})
+}
+
+func pageLink(c *gin.Context, page int) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Set("page", strconv.Itoa(page))
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
+
+// listError answers a failed list query: an unknown sort field is the
+// client's mistake, anything else is ours.
+func listError(c *gin.Context, err error, message string) {
+	if errors.Is(err, repositories.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
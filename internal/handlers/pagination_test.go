@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRespondList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		total          int64
+		wantTotalPages int
+		wantLinks      map[string]string
+	}{
+		{
+			name:           "first page",
+			query:          "?page=1&per_page=10&name=al",
+			total:          25,
+			wantTotalPages: 3,
+			wantLinks: map[string]string{
+				"self": "/items?name=al&page=1&per_page=10",
+				"next": "/items?name=al&page=2&per_page=10",
+			},
+		},
+		{
+			name:           "middle page",
+			query:          "?page=2&per_page=10&sort=-name",
+			total:          25,
+			wantTotalPages: 3,
+			wantLinks: map[string]string{
+				"self": "/items?page=2&per_page=10&sort=-name",
+				"next": "/items?page=3&per_page=10&sort=-name",
+				"prev": "/items?page=1&per_page=10&sort=-name",
+			},
+		},
+		{
+			name:           "last page",
+			query:          "?page=3&per_page=10",
+			total:          25,
+			wantTotalPages: 3,
+			wantLinks: map[string]string{
+				"self": "/items?page=3&per_page=10",
+				"prev": "/items?page=2&per_page=10",
+			},
+		},
+		{
+			name:           "past the end links back to the last page",
+			query:          "?page=9&per_page=10",
+			total:          25,
+			wantTotalPages: 3,
+			wantLinks: map[string]string{
+				"self": "/items?page=9&per_page=10",
+				"prev": "/items?page=3&per_page=10",
+			},
+		},
+		{
+			name:           "empty list",
+			query:          "?page=2",
+			total:          0,
+			wantTotalPages: 0,
+			wantLinks: map[string]string{
+				"self": "/items?page=2",
+				"prev": "/items?page=1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/items", func(c *gin.Context) {
+				opts, err := listOptions(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+				respondList(c, []string{}, tt.total, opts)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items"+tt.query, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+			}
+
+			var body struct {
+				Meta struct {
+					Total      int64 `json:"total"`
+					TotalPages int   `json:"total_pages"`
+				} `json:"meta"`
+				Links map[string]string `json:"links"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Meta.Total != tt.total || body.Meta.TotalPages != tt.wantTotalPages {
+				t.Errorf("total = %d in %d pages, want %d in %d", body.Meta.Total, body.Meta.TotalPages, tt.total, tt.wantTotalPages)
+			}
+			if !reflect.DeepEqual(body.Links, tt.wantLinks) {
+				t.Errorf("links = %v, want %v", body.Links, tt.wantLinks)
+			}
+		})
+	}
+}
+
+func TestListOptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		query   string
+		want    repositories.ListOptions
+		wantErr bool
+	}{
+		{"defaults", "", repositories.ListOptions{Page: 1, PerPage: defaultPerPage}, false},
+		{"page, size and sort", "?page=3&per_page=50&sort=-name", repositories.ListOptions{Page: 3, PerPage: 50, Sort: "-name"}, false},
+		{"page zero", "?page=0", repositories.ListOptions{}, true},
+		{"page not a number", "?page=two", repositories.ListOptions{}, true},
+		{"page size too large", "?per_page=101", repositories.ListOptions{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/items"+tt.query, nil)
+
+			got, err := listOptions(c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("listOptions() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("listOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		query        string
+		wantStatus   models.MatchStatus
+		wantPosition models.PlayerPosition
+		wantErr      string
+	}{
+		{"no filters", "", "", "", ""},
+		{"status", "?status=finished", models.Finished, "", ""},
+		{"position", "?position=midfielder", "", models.Midfield, ""},
+		{"misspelt status", "?status=finised", "", "", "invalid status"},
+		{"status in another case", "?status=Finished", "", "", "invalid status"},
+		{"unknown position", "?position=winger", "", "", "invalid position"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/items"+tt.query, nil)
+
+			var errs []string
+			filter, err := matchListFilter(c)
+			if err != nil {
+				errs = append(errs, err.Error())
+			}
+			position, err := queryPlayerPosition(c)
+			if err != nil {
+				errs = append(errs, err.Error())
+			}
+
+			if tt.wantErr != "" {
+				if len(errs) != 1 || errs[0] != tt.wantErr {
+					t.Errorf("errors = %q, want %q", errs, tt.wantErr)
+				}
+				return
+			}
+			if len(errs) > 0 {
+				t.Fatalf("errors = %q, want none", errs)
+			}
+			if filter.Status != tt.wantStatus || position != tt.wantPosition {
+				t.Errorf("status %q, position %q, want %q, %q", filter.Status, position, tt.wantStatus, tt.wantPosition)
+			}
+		})
+	}
+}
@@ -3,8 +3,10 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
 	"xyz-football/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -27,6 +29,27 @@ type CreatePlayerRequest struct {
 	Number   int                   `json:"number" binding:"required,min=1,max=99"`
 }
 
+type TransferPlayerRequest struct {
+	TeamID uint       `json:"team_id" binding:"required"`
+	Date   *time.Time `json:"date"`
+	Number int        `json:"number" binding:"omitempty,min=1,max=99"`
+}
+
+// The team name is only filled in where the list loads the team
+var playersCSV = csvExport[models.Player]{
+	filename: "players",
+	columns: []csvColumn[models.Player]{
+		{"id", func(p models.Player) string { return csvUint(p.ID) }},
+		{"name", func(p models.Player) string { return p.Name }},
+		{"team_id", func(p models.Player) string { return csvUint(p.TeamID) }},
+		{"team_name", func(p models.Player) string { return p.Team.Name }},
+		{"position", func(p models.Player) string { return string(p.Position) }},
+		{"number", func(p models.Player) string { return csvInt(p.Number) }},
+		{"height_cm", func(p models.Player) string { return csvFloat(p.HeightCM) }},
+		{"weight_kg", func(p models.Player) string { return csvFloat(p.WeightKG) }},
+	},
+}
+
 func (h *PlayerHandler) Create(c *gin.Context) {
 	var req CreatePlayerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -55,15 +78,47 @@ func (h *PlayerHandler) Create(c *gin.Context) {
 }
 
 func (h *PlayerHandler) List(c *gin.Context) {
-	players, err := h.service.GetAllPlayers()
+	opts, err := listOptions(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch players"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": players,
-	})
+	filter := repositories.PlayerListFilter{
+		Name: c.Query("name"),
+	}
+	if filter.Position, err = queryPlayerPosition(c); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.TeamID, err = queryUintPtr(c, "team_id"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if format.isCSV() {
+		err := playersCSV.stream(c, format, func(page int) ([]models.Player, error) {
+			players, _, err := h.service.ListPlayers(filter, exportOptions(opts, page))
+			return players, err
+		})
+		if err != nil {
+			listError(c, err, "failed to fetch players")
+		}
+		return
+	}
+
+	players, total, err := h.service.ListPlayers(filter, opts)
+	if err != nil {
+		listError(c, err, "failed to fetch players")
+		return
+	}
+
+	respondList(c, players, total, opts)
 }
 
 func (h *PlayerHandler) Get(c *gin.Context) {
@@ -141,6 +196,11 @@ func (h *PlayerHandler) ListByTeam(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team ID"})
 		return
 	}
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	players, err := h.service.GetPlayersByTeam(uint(teamID))
 	if err != nil {
@@ -148,7 +208,56 @@ func (h *PlayerHandler) ListByTeam(c *gin.Context) {
 		return
 	}
 
+	respondData(c, format, playersCSV, players, players)
+}
+
+func (h *PlayerHandler) Transfer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
+		return
+	}
+
+	var req TransferPlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transfer := services.Transfer{
+		TeamID: req.TeamID,
+		Number: req.Number,
+	}
+	if req.Date != nil {
+		transfer.Date = *req.Date
+	}
+
+	player, err := h.service.TransferPlayer(uint(id), transfer)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Player transferred successfully",
+		"data":    player,
+	})
+}
+
+func (h *PlayerHandler) Contracts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
+		return
+	}
+
+	contracts, err := h.service.GetContracts(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"data": players,
+		"data": contracts,
 	})
 }
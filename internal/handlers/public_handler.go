@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
+	"xyz-football/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PublicHandler serves the read-only API for fans and the website. It never
+// hands out models directly: every payload is trimmed down to what is shown
+// publicly, without bookkeeping fields such as timestamps and deleted_at.
+type PublicHandler struct {
+	teams   services.TeamService
+	players services.PlayerService
+	matches services.MatchService
+	reports services.ReportService
+}
+
+func NewPublicHandler(
+	teams services.TeamService,
+	players services.PlayerService,
+	matches services.MatchService,
+	reports services.ReportService,
+) *PublicHandler {
+	return &PublicHandler{
+		teams:   teams,
+		players: players,
+		matches: matches,
+		reports: reports,
+	}
+}
+
+type PublicTeam struct {
+	ID             uint   `json:"id"`
+	Name           string `json:"name"`
+	City           string `json:"city,omitempty"`
+	StadiumAddress string `json:"stadium_address,omitempty"`
+	FoundedYear    int    `json:"founded_year,omitempty"`
+	LogoURL        string `json:"logo_url,omitempty"`
+}
+
+// PublicTeamDetail is a team with its current squad.
+type PublicTeamDetail struct {
+	PublicTeam
+	Squad []PublicSquadPlayer `json:"squad"`
+}
+
+// PublicTeamRef names the team a player or match belongs to.
+type PublicTeamRef struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type PublicSquadPlayer struct {
+	ID       uint                  `json:"id"`
+	Name     string                `json:"name"`
+	Position models.PlayerPosition `json:"position"`
+	Number   int                   `json:"number"`
+}
+
+type PublicPlayer struct {
+	ID       uint                  `json:"id"`
+	Name     string                `json:"name"`
+	Position models.PlayerPosition `json:"position"`
+	Number   int                   `json:"number"`
+	HeightCM float64               `json:"height_cm,omitempty"`
+	WeightKG float64               `json:"weight_kg,omitempty"`
+	Team     PublicTeamRef         `json:"team"`
+}
+
+// PublicMatch is a fixture or result. The scores are left out until there is
+// one.
+type PublicMatch struct {
+	ID                 uint               `json:"id"`
+	MatchTime          string             `json:"match_time"`
+	Status             models.MatchStatus `json:"status"`
+	HomeTeam           PublicTeamRef      `json:"home_team"`
+	AwayTeam           PublicTeamRef      `json:"away_team"`
+	HomeScore          *int               `json:"home_score,omitempty"`
+	AwayScore          *int               `json:"away_score,omitempty"`
+	HomeExtraTimeScore *int               `json:"home_extra_time_score,omitempty"`
+	AwayExtraTimeScore *int               `json:"away_extra_time_score,omitempty"`
+	HomePenalties      *int               `json:"home_penalties,omitempty"`
+	AwayPenalties      *int               `json:"away_penalties,omitempty"`
+	SeasonID           *uint              `json:"season_id,omitempty"`
+	CompetitionID      *uint              `json:"competition_id,omitempty"`
+	Matchday           int                `json:"matchday,omitempty"`
+}
+
+func publicTeam(team models.Team) PublicTeam {
+	return PublicTeam{
+		ID:             team.ID,
+		Name:           team.Name,
+		City:           team.City,
+		StadiumAddress: team.StadiumAddr,
+		FoundedYear:    team.FoundedYear,
+		LogoURL:        team.LogoURL,
+	}
+}
+
+func publicPlayer(player models.Player) PublicPlayer {
+	return PublicPlayer{
+		ID:       player.ID,
+		Name:     player.Name,
+		Position: player.Position,
+		Number:   player.Number,
+		HeightCM: player.HeightCM,
+		WeightKG: player.WeightKG,
+		Team:     PublicTeamRef{ID: player.TeamID, Name: player.Team.Name},
+	}
+}
+
+func publicMatch(match models.Match) PublicMatch {
+	return PublicMatch{
+		ID:                 match.ID,
+		MatchTime:          match.MatchTime.Format(time.RFC3339),
+		Status:             match.Status,
+		HomeTeam:           PublicTeamRef{ID: match.HomeTeamID, Name: match.HomeTeam.Name},
+		AwayTeam:           PublicTeamRef{ID: match.AwayTeamID, Name: match.AwayTeam.Name},
+		HomeScore:          match.HomeScore,
+		AwayScore:          match.AwayScore,
+		HomeExtraTimeScore: match.HomeExtraTimeScore,
+		AwayExtraTimeScore: match.AwayExtraTimeScore,
+		HomePenalties:      match.HomePenalties,
+		AwayPenalties:      match.AwayPenalties,
+		SeasonID:           match.SeasonID,
+		CompetitionID:      match.CompetitionID,
+		Matchday:           match.Matchday,
+	}
+}
+
+func (h *PublicHandler) ListTeams(c *gin.Context) {
+	opts, err := listOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := repositories.TeamListFilter{
+		Name: c.Query("name"),
+		City: c.Query("city"),
+	}
+
+	teams, total, err := h.teams.ListTeams(filter, opts)
+	if err != nil {
+		listError(c, err, "failed to fetch teams")
+		return
+	}
+
+	data := make([]PublicTeam, len(teams))
+	for i, team := range teams {
+		data[i] = publicTeam(team)
+	}
+	respondList(c, data, total, opts)
+}
+
+func (h *PublicHandler) GetTeam(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team ID"})
+		return
+	}
+
+	team, err := h.teams.GetTeamByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "team not found"})
+		return
+	}
+	squad, err := h.players.GetPlayersByTeam(team.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch players"})
+		return
+	}
+
+	detail := PublicTeamDetail{PublicTeam: publicTeam(*team), Squad: make([]PublicSquadPlayer, len(squad))}
+	for i, player := range squad {
+		detail.Squad[i] = PublicSquadPlayer{
+			ID:       player.ID,
+			Name:     player.Name,
+			Position: player.Position,
+			Number:   player.Number,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": detail,
+	})
+}
+
+func (h *PublicHandler) ListPlayers(c *gin.Context) {
+	opts, err := listOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := repositories.PlayerListFilter{
+		Name: c.Query("name"),
+	}
+	if filter.Position, err = queryPlayerPosition(c); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.TeamID, err = queryUintPtr(c, "team_id"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	players, total, err := h.players.ListPlayers(filter, opts)
+	if err != nil {
+		listError(c, err, "failed to fetch players")
+		return
+	}
+
+	data := make([]PublicPlayer, len(players))
+	for i, player := range players {
+		data[i] = publicPlayer(player)
+	}
+	respondList(c, data, total, opts)
+}
+
+func (h *PublicHandler) GetPlayer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
+		return
+	}
+
+	player, err := h.players.GetPlayerByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": publicPlayer(*player),
+	})
+}
+
+// ListMatches lists fixtures and results, with the filters of the match list.
+func (h *PublicHandler) ListMatches(c *gin.Context) {
+	opts, err := listOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter, err := matchListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	matches, total, err := h.matches.ListMatches(filter, opts)
+	if err != nil {
+		listError(c, err, "failed to fetch matches")
+		return
+	}
+
+	data := make([]PublicMatch, len(matches))
+	for i, match := range matches {
+		data[i] = publicMatch(match)
+	}
+	respondList(c, data, total, opts)
+}
+
+// GetMatch answers with the match report, which already carries the score
+// line and goals without any internal fields.
+func (h *PublicHandler) GetMatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match ID"})
+		return
+	}
+
+	report, err := h.reports.GetMatchReport(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "match not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
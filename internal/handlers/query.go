@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"xyz-football/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// queryUintPtr reads an optional numeric ID from the query string.
+// A missing parameter yields nil, a malformed one an error.
+func queryUintPtr(c *gin.Context, key string) (*uint, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	v, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+
+	id := uint(v)
+	return &id, nil
+}
+
+// queryMatchStatus reads an optional match status filter. A missing
+// parameter yields "", an unknown status an error.
+func queryMatchStatus(c *gin.Context) (models.MatchStatus, error) {
+	status := models.MatchStatus(c.Query("status"))
+	if status != "" && !status.IsKnown() {
+		return "", errors.New("invalid status")
+	}
+	return status, nil
+}
+
+// queryPlayerPosition reads an optional player position filter. A missing
+// parameter yields "", an unknown position an error.
+func queryPlayerPosition(c *gin.Context) (models.PlayerPosition, error) {
+	position := models.PlayerPosition(c.Query("position"))
+	if position != "" && !position.IsKnown() {
+		return "", errors.New("invalid position")
+	}
+	return position, nil
+}
+
+// queryTimePtr reads an optional timestamp from the query string, either
+// RFC 3339 or a plain date. A plain date stands for the end of that day (UTC),
+// so "as_of=2026-03-01" still includes that day's matches.
+func queryTimePtr(c *gin.Context, key string) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	day, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s, expected a date (YYYY-MM-DD) or RFC 3339 timestamp", key)
+	}
+	end := day.Add(24*time.Hour - time.Nanosecond)
+	return &end, nil
+}
+
+// queryLimit reads the size of a leaderboard, 10 unless a positive limit is
+// given, and no more than the route's "max_limit" if it sets one.
+func queryLimit(c *gin.Context) int {
+	limit := 10 // default limit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	if max := c.GetInt("max_limit"); max > 0 && limit > max {
+		limit = max
+	}
+	return limit
+}
+
+// queryLastN reads how many of the most recent matches to cover from "last".
+// A missing parameter yields 0, leaving the default to the service.
+func queryLastN(c *gin.Context) (int, error) {
+	raw := c.Query("last")
+	if raw == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		return 0, errors.New("invalid last, expected a positive number")
+	}
+	return n, nil
+}
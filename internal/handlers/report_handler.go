@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"xyz-football/internal/services"
@@ -17,38 +20,327 @@ func NewReportHandler(service services.ReportService) *ReportHandler {
 	return &ReportHandler{service: service}
 }
 
+// CSV layouts of the reports.
+var (
+	standingsCSV = csvExport[services.TeamStanding]{
+		filename: "standings",
+		columns: []csvColumn[services.TeamStanding]{
+			{"position", func(r services.TeamStanding) string { return csvInt(r.Position) }},
+			{"team_id", func(r services.TeamStanding) string { return csvUint(r.TeamID) }},
+			{"team_name", func(r services.TeamStanding) string { return r.TeamName }},
+			{"played", func(r services.TeamStanding) string { return csvInt(r.Played) }},
+			{"won", func(r services.TeamStanding) string { return csvInt(r.Won) }},
+			{"drawn", func(r services.TeamStanding) string { return csvInt(r.Drawn) }},
+			{"lost", func(r services.TeamStanding) string { return csvInt(r.Lost) }},
+			{"goals_for", func(r services.TeamStanding) string { return csvInt(r.GoalsFor) }},
+			{"goals_away", func(r services.TeamStanding) string { return csvInt(r.GoalsAway) }},
+			{"goal_difference", func(r services.TeamStanding) string { return csvInt(r.GoalDifference) }},
+			{"away_goals_for", func(r services.TeamStanding) string { return csvInt(r.AwayGoalsFor) }},
+			{"points", func(r services.TeamStanding) string { return csvInt(r.Points) }},
+			{"fair_play_points", func(r services.TeamStanding) string { return csvInt(r.FairPlayPoints) }},
+			{"tie_breaker", func(r services.TeamStanding) string { return r.TieBreaker }},
+			{"form", func(r services.TeamStanding) string { return r.Form }},
+		},
+	}
+
+	standingsHistoryCSV = csvExport[services.StandingsSnapshot]{
+		filename: "standings-history",
+		columns: []csvColumn[services.StandingsSnapshot]{
+			{"round", func(r services.StandingsSnapshot) string { return csvInt(r.Round) }},
+			{"matchday", func(r services.StandingsSnapshot) string { return csvOptionalInt(r.Matchday) }},
+			{"date", func(r services.StandingsSnapshot) string { return r.Date }},
+			{"position", func(r services.StandingsSnapshot) string { return csvInt(r.Position) }},
+			{"played", func(r services.StandingsSnapshot) string { return csvInt(r.Played) }},
+			{"points", func(r services.StandingsSnapshot) string { return csvInt(r.Points) }},
+		},
+	}
+
+	topScorersCSV = csvExport[services.PlayerGoals]{
+		filename: "top-scorers",
+		columns: []csvColumn[services.PlayerGoals]{
+			{"player_id", func(r services.PlayerGoals) string { return csvUint(r.PlayerID) }},
+			{"player_name", func(r services.PlayerGoals) string { return r.PlayerName }},
+			{"team_id", func(r services.PlayerGoals) string { return csvUint(r.TeamID) }},
+			{"team_name", func(r services.PlayerGoals) string { return r.TeamName }},
+			{"goals", func(r services.PlayerGoals) string { return csvInt(r.Goals) }},
+			{"minutes_played", func(r services.PlayerGoals) string { return csvOptionalInt(r.MinutesPlayed) }},
+		},
+	}
+
+	topAssistsCSV = csvExport[services.PlayerAssists]{
+		filename: "top-assists",
+		columns: []csvColumn[services.PlayerAssists]{
+			{"player_id", func(r services.PlayerAssists) string { return csvUint(r.PlayerID) }},
+			{"player_name", func(r services.PlayerAssists) string { return r.PlayerName }},
+			{"team_id", func(r services.PlayerAssists) string { return csvUint(r.TeamID) }},
+			{"team_name", func(r services.PlayerAssists) string { return r.TeamName }},
+			{"assists", func(r services.PlayerAssists) string { return csvInt(r.Assists) }},
+			{"minutes_played", func(r services.PlayerAssists) string { return csvOptionalInt(r.MinutesPlayed) }},
+		},
+	}
+
+	goalContributionsCSV = csvExport[services.PlayerContributions]{
+		filename: "goal-contributions",
+		columns: []csvColumn[services.PlayerContributions]{
+			{"player_id", func(r services.PlayerContributions) string { return csvUint(r.PlayerID) }},
+			{"player_name", func(r services.PlayerContributions) string { return r.PlayerName }},
+			{"team_id", func(r services.PlayerContributions) string { return csvUint(r.TeamID) }},
+			{"team_name", func(r services.PlayerContributions) string { return r.TeamName }},
+			{"goals", func(r services.PlayerContributions) string { return csvInt(r.Goals) }},
+			{"assists", func(r services.PlayerContributions) string { return csvInt(r.Assists) }},
+			{"contributions", func(r services.PlayerContributions) string { return csvInt(r.Contributions) }},
+			{"minutes_played", func(r services.PlayerContributions) string { return csvOptionalInt(r.MinutesPlayed) }},
+		},
+	}
+
+	// One row per match; the goals are left out
+	matchReportsCSV = csvExport[services.MatchReport]{
+		filename: "match-reports",
+		columns: []csvColumn[services.MatchReport]{
+			{"match_id", func(r services.MatchReport) string { return csvUint(r.MatchID) }},
+			{"match_time", func(r services.MatchReport) string { return r.MatchTime }},
+			{"status", func(r services.MatchReport) string { return r.Status }},
+			{"home_team", func(r services.MatchReport) string { return r.HomeTeam }},
+			{"away_team", func(r services.MatchReport) string { return r.AwayTeam }},
+			{"home_score", func(r services.MatchReport) string { return csvIntPtr(r.HomeScore) }},
+			{"away_score", func(r services.MatchReport) string { return csvIntPtr(r.AwayScore) }},
+			{"home_extra_time_score", func(r services.MatchReport) string { return csvIntPtr(r.HomeExtraTimeScore) }},
+			{"away_extra_time_score", func(r services.MatchReport) string { return csvIntPtr(r.AwayExtraTimeScore) }},
+			{"home_penalties", func(r services.MatchReport) string { return csvIntPtr(r.HomePenalties) }},
+			{"away_penalties", func(r services.MatchReport) string { return csvIntPtr(r.AwayPenalties) }},
+			{"final_score", func(r services.MatchReport) string { return r.FinalScore }},
+			{"result", func(r services.MatchReport) string { return r.Result }},
+			{"top_scorer", func(r services.MatchReport) string {
+				if r.TopScorer == nil {
+					return ""
+				}
+				return *r.TopScorer
+			}},
+			{"home_wins_until_this_match", func(r services.MatchReport) string { return csvInt(r.HomeWinsUntilThisMatch) }},
+			{"away_wins_until_this_match", func(r services.MatchReport) string { return csvInt(r.AwayWinsUntilThisMatch) }},
+		},
+	}
+
+	// The meetings only; the records are easy to sum up from them
+	headToHeadCSV = csvExport[services.HeadToHeadMeeting]{
+		filename: "head-to-head",
+		columns: []csvColumn[services.HeadToHeadMeeting]{
+			{"match_id", func(r services.HeadToHeadMeeting) string { return csvUint(r.MatchID) }},
+			{"match_time", func(r services.HeadToHeadMeeting) string { return r.MatchTime }},
+			{"competition", func(r services.HeadToHeadMeeting) string { return r.Competition }},
+			{"home_team", func(r services.HeadToHeadMeeting) string { return r.HomeTeam }},
+			{"away_team", func(r services.HeadToHeadMeeting) string { return r.AwayTeam }},
+			{"score", func(r services.HeadToHeadMeeting) string { return r.Score }},
+			{"winner_team_id", func(r services.HeadToHeadMeeting) string { return csvUintPtr(r.WinnerTeamID) }},
+		},
+	}
+)
+
+// narrowingParams are the report parameters only some reports understand.
+// Every other report refuses them rather than ignores them, so nobody takes
+// an unfiltered answer for a filtered one.
+var narrowingParams = []string{"team_id", "tie_break", "as_of", "venue", "last"}
+
+// reportFilter builds the season/competition scope shared by all reports,
+// along with team_id and tie_break when the report accepts them. Any other
+// narrowing parameter is refused.
+func reportFilter(c *gin.Context, accepts ...string) (services.ReportFilter, error) {
+	var filter services.ReportFilter
+	var err error
+
+	for _, key := range narrowingParams {
+		if c.Query(key) != "" && !slices.Contains(accepts, key) {
+			return filter, fmt.Errorf("%s does not apply to this report", key)
+		}
+	}
+
+	if filter.SeasonID, err = queryUintPtr(c, "season_id"); err != nil {
+		return filter, err
+	}
+	if filter.CompetitionID, err = queryUintPtr(c, "competition_id"); err != nil {
+		return filter, err
+	}
+	if filter.TeamID, err = queryUintPtr(c, "team_id"); err != nil {
+		return filter, err
+	}
+	filter.IncludeFriendlies = c.Query("include_friendlies") == "true"
+
+	switch tieBreak := services.LeaderboardTieBreak(c.Query("tie_break")); tieBreak {
+	case "", services.TieBreakByName, services.TieBreakByMinutes:
+		filter.TieBreak = tieBreak
+	default:
+		return filter, errors.New("invalid tie_break, expected name or minutes")
+	}
+
+	return filter, nil
+}
+
+// leaderboardFilter is reportFilter for the player leaderboards, which can
+// be narrowed to a team and break ties by minutes played.
+func leaderboardFilter(c *gin.Context) (services.ReportFilter, error) {
+	return reportFilter(c, "team_id", "tie_break")
+}
+
+// standingsFilter is reportFilter with the standings parameters: as_of,
+// venue and last.
+func standingsFilter(c *gin.Context, accepts ...string) (services.ReportFilter, error) {
+	filter, err := reportFilter(c, append(accepts, "as_of", "venue", "last")...)
+	if err != nil {
+		return filter, err
+	}
+
+	if filter.AsOf, err = queryTimePtr(c, "as_of"); err != nil {
+		return filter, err
+	}
+	if filter.LastMatches, err = queryLastN(c); err != nil {
+		return filter, err
+	}
+
+	switch venue := services.Venue(c.Query("venue")); venue {
+	case "", services.VenueAll, services.VenueHome, services.VenueAway:
+		filter.Venue = venue
+	default:
+		return filter, errors.New("invalid venue, expected home, away or all")
+	}
+
+	return filter, nil
+}
+
 func (h *ReportHandler) GetStandings(c *gin.Context) {
-	standings, err := h.service.GetStandings()
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := standingsFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	standings, err := h.service.GetStandings(filter)
+	if errors.Is(err, services.ErrCompetitionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch standings"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": standings,
-	})
+	respondData(c, format, standingsCSV, standings, standings)
+}
+
+func (h *ReportHandler) GetStandingsHistory(c *gin.Context) {
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := standingsFilter(c, "team_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.TeamID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "team_id is required"})
+		return
+	}
+
+	history, err := h.service.GetStandingsHistory(*filter.TeamID, filter)
+	if errors.Is(err, services.ErrCompetitionNotFound) || errors.Is(err, services.ErrTeamNotInStandings) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch standings history"})
+		return
+	}
+
+	respondData(c, format, standingsHistoryCSV, history, history)
 }
 
 func (h *ReportHandler) GetTopScorers(c *gin.Context) {
-	limit := 10 // default limit
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
-			limit = l
-		}
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	scorers, err := h.service.GetTopScorers(limit)
+	limit := queryLimit(c)
+
+	filter, err := leaderboardFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scorers, err := h.service.GetTopScorers(limit, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch top scorers"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": scorers,
-	})
+	respondData(c, format, topScorersCSV, scorers, scorers)
+}
+
+func (h *ReportHandler) GetTopAssists(c *gin.Context) {
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit := queryLimit(c)
+
+	filter, err := leaderboardFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assists, err := h.service.GetTopAssists(limit, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch top assists"})
+		return
+	}
+
+	respondData(c, format, topAssistsCSV, assists, assists)
+}
+
+func (h *ReportHandler) GetGoalContributions(c *gin.Context) {
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit := queryLimit(c)
+
+	filter, err := leaderboardFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contributions, err := h.service.GetGoalContributions(limit, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch goal contributions"})
+		return
+	}
+
+	respondData(c, format, goalContributionsCSV, contributions, contributions)
 }
 
 func (h *ReportHandler) GetMatchReport(c *gin.Context) {
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match ID"})
@@ -61,9 +353,72 @@ func (h *ReportHandler) GetMatchReport(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": report,
-	})
+	export := matchReportsCSV
+	export.filename = fmt.Sprintf("match-report-%d", report.MatchID)
+	respondData(c, format, export, []services.MatchReport{*report}, report)
+}
+
+func (h *ReportHandler) GetMatchReports(c *gin.Context) {
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := reportFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reports, err := h.service.GetMatchReports(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch match reports"})
+		return
+	}
+
+	respondData(c, format, matchReportsCSV, reports, reports)
+}
+
+func (h *ReportHandler) GetHeadToHead(c *gin.Context) {
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	teamA, err := queryUintPtr(c, "team_a")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	teamB, err := queryUintPtr(c, "team_b")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if teamA == nil || teamB == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "team_a and team_b are required"})
+		return
+	}
+	if *teamA == *teamB {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "team_a and team_b must be different teams"})
+		return
+	}
+
+	filter, err := reportFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h2h, err := h.service.GetHeadToHead(*teamA, *teamB, queryLimit(c), filter)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	respondData(c, format, headToHeadCSV, h2h.Matches, h2h)
 }
 
 // Response helper functions
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"xyz-football/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReportFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	filters := map[string]func(c *gin.Context) (services.ReportFilter, error){
+		"report":      func(c *gin.Context) (services.ReportFilter, error) { return reportFilter(c) },
+		"leaderboard": leaderboardFilter,
+		"standings":   func(c *gin.Context) (services.ReportFilter, error) { return standingsFilter(c) },
+		"history":     func(c *gin.Context) (services.ReportFilter, error) { return standingsFilter(c, "team_id") },
+	}
+
+	tests := []struct {
+		query   string
+		refused []string // filters that answer 400
+	}{
+		{"?season_id=1&competition_id=2&include_friendlies=true", nil},
+		{"?team_id=3", []string{"report", "standings"}},
+		{"?tie_break=minutes", []string{"report", "standings", "history"}},
+		{"?as_of=2025-01-01", []string{"report", "leaderboard"}},
+		{"?venue=home", []string{"report", "leaderboard"}},
+		{"?last=5", []string{"report", "leaderboard"}},
+		{"?season_id=x", []string{"report", "leaderboard", "standings", "history"}},
+		{"?tie_break=goals", []string{"report", "leaderboard", "standings", "history"}},
+	}
+
+	for _, tt := range tests {
+		for name, filter := range filters {
+			t.Run(name+tt.query, func(t *testing.T) {
+				c, _ := gin.CreateTestContext(httptest.NewRecorder())
+				c.Request = httptest.NewRequest(http.MethodGet, "/reports"+tt.query, nil)
+
+				_, err := filter(c)
+				refused := false
+				for _, r := range tt.refused {
+					refused = refused || r == name
+				}
+				if (err != nil) != refused {
+					t.Errorf("error = %v, want refused %v", err, refused)
+				}
+			})
+		}
+	}
+}
+
+// fakeReportService fails the standings with err.
+type fakeReportService struct {
+	services.ReportService
+	err error
+}
+
+func (f *fakeReportService) GetStandings(filter services.ReportFilter) ([]services.TeamStanding, error) {
+	return nil, f.err
+}
+
+func (f *fakeReportService) GetStandingsHistory(teamID uint, filter services.ReportFilter) ([]services.StandingsSnapshot, error) {
+	return nil, f.err
+}
+
+func TestReportHandlerStandingsErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		path       string
+		err        error
+		wantStatus int
+	}{
+		{"competition not found", "/standings?competition_id=9", services.ErrCompetitionNotFound, http.StatusNotFound},
+		{"database error", "/standings", errors.New("connection refused"), http.StatusInternalServerError},
+		{"history of an unknown competition", "/standings/history?team_id=1&competition_id=9", services.ErrCompetitionNotFound, http.StatusNotFound},
+		{"team not in the standings", "/standings/history?team_id=1", services.ErrTeamNotInStandings, http.StatusNotFound},
+		{"history database error", "/standings/history?team_id=1", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewReportHandler(&fakeReportService{err: tt.err})
+			r := gin.New()
+			r.GET("/standings", handler.GetStandings)
+			r.GET("/standings/history", handler.GetStandingsHistory)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusInternalServerError && strings.Contains(w.Body.String(), tt.err.Error()) {
+				t.Errorf("body %s leaks the error", w.Body.String())
+			}
+		})
+	}
+}
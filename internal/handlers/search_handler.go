@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"xyz-football/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	service services.SearchService
+}
+
+func NewSearchHandler(service services.SearchService) *SearchHandler {
+	return &SearchHandler{service: service}
+}
+
+func (h *SearchHandler) Search(c *gin.Context) {
+	results, err := h.service.Search(c.Query("q"), queryLimit(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": results,
+	})
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeasonHandler struct {
+	service services.SeasonService
+}
+
+func NewSeasonHandler(service services.SeasonService) *SeasonHandler {
+	return &SeasonHandler{service: service}
+}
+
+type CreateSeasonRequest struct {
+	Name      string    `json:"name" binding:"required"`
+	StartDate time.Time `json:"start_date" binding:"required"`
+	EndDate   time.Time `json:"end_date" binding:"required"`
+}
+
+func (h *SeasonHandler) Create(c *gin.Context) {
+	var req CreateSeasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	season := &models.Season{
+		Name:      req.Name,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	}
+
+	if err := h.service.CreateSeason(season); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Season created successfully",
+		"data":    season,
+	})
+}
+
+var seasonsCSV = csvExport[models.Season]{
+	filename: "seasons",
+	columns: []csvColumn[models.Season]{
+		{"id", func(s models.Season) string { return csvUint(s.ID) }},
+		{"name", func(s models.Season) string { return s.Name }},
+		{"start_date", func(s models.Season) string { return csvTime(s.StartDate) }},
+		{"end_date", func(s models.Season) string { return csvTime(s.EndDate) }},
+	},
+}
+
+func (h *SeasonHandler) List(c *gin.Context) {
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	seasons, err := h.service.GetAllSeasons()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch seasons"})
+		return
+	}
+
+	respondData(c, format, seasonsCSV, seasons, seasons)
+}
+
+func (h *SeasonHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid season ID"})
+		return
+	}
+
+	season, err := h.service.GetSeasonByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "season not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": season,
+	})
+}
+
+func (h *SeasonHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid season ID"})
+		return
+	}
+
+	var req CreateSeasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	season := &models.Season{
+		ID:        uint(id),
+		Name:      req.Name,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	}
+
+	if err := h.service.UpdateSeason(season); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Season updated successfully",
+		"data":    season,
+	})
+}
+
+func (h *SeasonHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid season ID"})
+		return
+	}
+
+	if err := h.service.DeleteSeason(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Season deleted successfully",
+	})
+}
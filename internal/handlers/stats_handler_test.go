@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"xyz-football/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeStatsService records what the handler asked for.
+type fakeStatsService struct {
+	teamID     uint
+	filter     services.ReportFilter
+	formLength int
+}
+
+func (f *fakeStatsService) GetPlayerStats(playerID uint, filter services.ReportFilter) (*services.PlayerStats, error) {
+	return &services.PlayerStats{}, nil
+}
+
+func (f *fakeStatsService) GetTeamStats(teamID uint, filter services.ReportFilter, formLength int) (*services.TeamStats, error) {
+	f.teamID, f.filter, f.formLength = teamID, filter, formLength
+	return &services.TeamStats{TeamID: teamID}, nil
+}
+
+func TestStatsHandlerGetTeamStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		wantStatus     int
+		wantFormLength int
+	}{
+		{"default form length", "", http.StatusOK, 0},
+		{"form length", "?last=3", http.StatusOK, 3},
+		{"form length with a season", "?last=10&season_id=2", http.StatusOK, 10},
+		{"invalid form length", "?last=0", http.StatusBadRequest, 0},
+		{"form length not a number", "?last=five", http.StatusBadRequest, 0},
+		{"as_of is for the standings", "?as_of=2025-01-01T00:00:00Z", http.StatusBadRequest, 0},
+		{"venue is for the standings", "?venue=home", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeStatsService{}
+			r := gin.New()
+			r.GET("/teams/:id/stats", NewStatsHandler(service).GetTeamStats)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/teams/7/stats"+tt.query, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if service.teamID != 7 || service.formLength != tt.wantFormLength {
+				t.Errorf("GetTeamStats(team %d, form length %d), want team 7, form length %d",
+					service.teamID, service.formLength, tt.wantFormLength)
+			}
+			if service.filter.LastMatches != 0 {
+				t.Errorf("last also narrowed the matches to %d", service.filter.LastMatches)
+			}
+		})
+	}
+}
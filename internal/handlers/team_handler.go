@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
 	"xyz-football/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -26,6 +27,18 @@ type CreateTeamRequest struct {
 	City        string `json:"city"`
 }
 
+var teamsCSV = csvExport[models.Team]{
+	filename: "teams",
+	columns: []csvColumn[models.Team]{
+		{"id", func(t models.Team) string { return csvUint(t.ID) }},
+		{"name", func(t models.Team) string { return t.Name }},
+		{"city", func(t models.Team) string { return t.City }},
+		{"stadium_address", func(t models.Team) string { return t.StadiumAddr }},
+		{"founded_year", func(t models.Team) string { return csvOptionalInt(t.FoundedYear) }},
+		{"logo_url", func(t models.Team) string { return t.LogoURL }},
+	},
+}
+
 func (h *TeamHandler) Create(c *gin.Context) {
 	var req CreateTeamRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -53,15 +66,40 @@ func (h *TeamHandler) Create(c *gin.Context) {
 }
 
 func (h *TeamHandler) List(c *gin.Context) {
-	teams, err := h.service.GetAllTeams()
+	opts, err := listOptions(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch teams"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": teams,
-	})
+	filter := repositories.TeamListFilter{
+		Name: c.Query("name"),
+		City: c.Query("city"),
+	}
+
+	if format.isCSV() {
+		err := teamsCSV.stream(c, format, func(page int) ([]models.Team, error) {
+			teams, _, err := h.service.ListTeams(filter, exportOptions(opts, page))
+			return teams, err
+		})
+		if err != nil {
+			listError(c, err, "Failed to fetch teams")
+		}
+		return
+	}
+
+	teams, total, err := h.service.ListTeams(filter, opts)
+	if err != nil {
+		listError(c, err, "Failed to fetch teams")
+		return
+	}
+
+	respondList(c, teams, total, opts)
 }
 
 func (h *TeamHandler) Get(c *gin.Context) {
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(Cache(time.Minute))
+	r.GET("/teams", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"data": "teams"}) })
+	r.GET("/missing", func(c *gin.Context) { c.JSON(http.StatusNotFound, gin.H{"error": "not found"}) })
+	r.POST("/teams", func(c *gin.Context) { c.JSON(http.StatusCreated, gin.H{"data": "created"}) })
+
+	serve := func(method, path, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := serve(http.MethodGet, "/teams", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || first.Body.String() != `{"data":"teams"}` {
+		t.Fatalf("GET = %d %s", first.Code, first.Body.String())
+	}
+	if etag == "" {
+		t.Fatal("no ETag on a successful GET")
+	}
+	if got := first.Header().Get("Cache-Control"); got != "public, max-age=60" {
+		t.Errorf("Cache-Control = %q, want %q", got, "public, max-age=60")
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		ifNoneMatch string
+		wantStatus  int
+		wantBody    bool
+		wantCached  bool
+	}{
+		{"same ETag", http.MethodGet, "/teams", etag, http.StatusNotModified, false, true},
+		{"weak ETag among others", http.MethodGet, "/teams", `"other", W/` + etag, http.StatusNotModified, false, true},
+		{"any ETag", http.MethodGet, "/teams", "*", http.StatusNotModified, false, true},
+		{"stale ETag", http.MethodGet, "/teams", `"stale"`, http.StatusOK, true, true},
+		{"error response", http.MethodGet, "/missing", "", http.StatusNotFound, true, false},
+		{"error response with ETag", http.MethodGet, "/missing", "*", http.StatusNotFound, true, false},
+		{"not a GET", http.MethodPost, "/teams", "", http.StatusCreated, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(tt.method, tt.path, tt.ifNoneMatch)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if gotBody := w.Body.Len() > 0; gotBody != tt.wantBody {
+				t.Errorf("body %q, want a body %v", w.Body.String(), tt.wantBody)
+			}
+			if gotCached := w.Header().Get("Cache-Control") != ""; gotCached != tt.wantCached {
+				t.Errorf("Cache-Control = %q, want caching %v", w.Header().Get("Cache-Control"), tt.wantCached)
+			}
+			if tt.wantCached && w.Header().Get("ETag") != etag {
+				t.Errorf("ETag = %q, want %q", w.Header().Get("ETag"), etag)
+			}
+		})
+	}
+}
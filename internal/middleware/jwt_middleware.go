@@ -3,12 +3,19 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"xyz-football/internal/models"
 	"xyz-football/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-func JWTAuthMiddleware() gin.HandlerFunc {
+// TokenVerifier checks an access token: its signature and claims, and that
+// it has not been revoked by logging out.
+type TokenVerifier interface {
+	VerifyAccessToken(token string) (*utils.JWTClaim, error)
+}
+
+func JWTAuthMiddleware(tokens TokenVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -25,7 +32,7 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		claims, err := utils.ValidateToken(tokenString)
+		claims, err := tokens.VerifyAccessToken(tokenString)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
@@ -34,6 +41,8 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 
 		// Simpan user ID ke context
 		c.Set("user_id", claims.UserID)
+		c.Set("role", models.Role(claims.Role))
+		c.Set("session_id", claims.SessionID)
 		c.Next()
 	}
 }
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"xyz-football/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authorize guards a route group after JWTAuthMiddleware: reading (GET and
+// HEAD) takes the read permission, any other method the write permission.
+func Authorize(read, write models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permission := write
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			permission = read
+		}
+		check(c, permission)
+	}
+}
+
+// Require guards a route, or a group, with a single permission.
+func Require(permission models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		check(c, permission)
+	}
+}
+
+func check(c *gin.Context, permission models.Permission) {
+	role, _ := c.Get("role")
+	if r, ok := role.(models.Role); !ok || !r.Can(permission) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		c.Abort()
+		return
+	}
+	c.Next()
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"xyz-football/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuthorize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		role   interface{} // as JWTAuthMiddleware sets it, nil for none
+		method string
+		want   int
+	}{
+		{"read only may read", models.RoleReadOnly, http.MethodGet, http.StatusOK},
+		{"read only may use HEAD", models.RoleReadOnly, http.MethodHead, http.StatusOK},
+		{"read only may not write", models.RoleReadOnly, http.MethodPost, http.StatusForbidden},
+		{"read only may not delete", models.RoleReadOnly, http.MethodDelete, http.StatusForbidden},
+		{"match official may not manage the league", models.RoleMatchOfficial, http.MethodPut, http.StatusForbidden},
+		{"league admin may write", models.RoleLeagueAdmin, http.MethodPost, http.StatusOK},
+		{"super admin may write", models.RoleSuperAdmin, http.MethodPatch, http.StatusOK},
+		{"unknown role", models.Role("coach"), http.MethodGet, http.StatusForbidden},
+		{"role as a plain string", "super_admin", http.MethodGet, http.StatusForbidden},
+		{"no role", nil, http.MethodGet, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if tt.role != nil {
+					c.Set("role", tt.role)
+				}
+			})
+			r.Handle(tt.method, "/teams", Authorize(models.PermRead, models.PermManageLeague), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, "/teams", nil))
+			if w.Code != tt.want {
+				t.Errorf("%s as %v = %d, want %d", tt.method, tt.role, w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// PublicQuery bounds what an anonymous caller can ask for: leaderboards are
+// cut off at maxLimit rows and answers are JSON only, since a CSV export
+// covers a whole list. Handlers read these from the context, see queryLimit
+// and negotiateFormat.
+func PublicQuery(maxLimit int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("max_limit", maxLimit)
+		c.Set("json_only", true)
+		c.Next()
+	}
+}
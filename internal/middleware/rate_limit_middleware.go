@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit lets every client, told apart by IP, make a burst of limit
+// requests, refilled evenly over window. The IP is gin's ClientIP, which
+// only follows X-Forwarded-For from the engine's trusted proxies. Each call
+// keeps its own counts, so route groups using separate limiters do not eat
+// into each other.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	limiter := newRateLimiter(limit, window)
+
+	return func(c *gin.Context) {
+		allowed, tokens := limiter.take(c.ClientIP(), time.Now())
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(int(tokens)))
+		if !allowed {
+			retryAfter := math.Ceil((1 - tokens) / limiter.perSecond)
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter)))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimiter holds a token bucket per client.
+type rateLimiter struct {
+	limit     int
+	window    time.Duration
+	perSecond float64
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:     limit,
+		window:    window,
+		perSecond: float64(limit) / window.Seconds(),
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// take spends one of the client's tokens if it has one left, and returns
+// whether it did and how many tokens remain.
+func (l *rateLimiter) take(client string, now time.Time) (bool, float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Clients quiet for a whole window are back to a full bucket anyway
+	if now.Sub(l.lastSweep) > l.window {
+		for key, b := range l.buckets {
+			if now.Sub(b.last) > l.window {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit), last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(float64(l.limit), b.tokens+now.Sub(b.last).Seconds()*l.perSecond)
+	b.last = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return allowed, b.tokens
+}
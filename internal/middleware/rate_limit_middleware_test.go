@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimiterTake(t *testing.T) {
+	start := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		// requests are made at these offsets from start, all by one client
+		at   []time.Duration
+		want []bool
+	}{
+		{"burst up to the limit", []time.Duration{0, 0, 0}, []bool{true, true, true}},
+		{"over the limit", []time.Duration{0, 0, 0, 0}, []bool{true, true, true, false}},
+		{"refilled after a third of the window", []time.Duration{0, 0, 0, 0, 20 * time.Second}, []bool{true, true, true, false, true}},
+		{"not yet refilled", []time.Duration{0, 0, 0, 0, 19 * time.Second}, []bool{true, true, true, false, false}},
+		{"refill stops at the limit", []time.Duration{0, time.Hour, time.Hour, time.Hour, time.Hour}, []bool{true, true, true, true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := newRateLimiter(3, time.Minute)
+			limiter.lastSweep = start
+
+			for i, offset := range tt.at {
+				if allowed, _ := limiter.take("1.2.3.4", start.Add(offset)); allowed != tt.want[i] {
+					t.Errorf("request %d allowed = %v, want %v", i+1, allowed, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	start := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(3, time.Minute)
+	limiter.lastSweep = start
+
+	limiter.take("quiet", start)
+	limiter.take("busy", start)
+	limiter.take("busy", start.Add(50*time.Second))
+	if len(limiter.buckets) != 2 {
+		t.Fatalf("%d buckets before the sweep, want 2", len(limiter.buckets))
+	}
+
+	limiter.take("busy", start.Add(70*time.Second))
+	if _, ok := limiter.buckets["quiet"]; ok {
+		t.Error("a client quiet for a whole window was kept")
+	}
+	if _, ok := limiter.buckets["busy"]; !ok {
+		t.Error("an active client was swept")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/public", RateLimit(2, time.Minute), ok)
+	r.GET("/other", RateLimit(2, time.Minute), ok)
+
+	get := func(path, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := get("/public", "10.0.0.1"); w.Code != http.StatusOK {
+			t.Fatalf("request %d = %d, want %d", i+1, w.Code, http.StatusOK)
+		}
+	}
+
+	w := get("/public", "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over the limit = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got := w.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want %q", got, "30")
+	}
+	if got := w.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("X-RateLimit-Remaining = %q, want %q", got, "0")
+	}
+
+	if w := get("/public", "10.0.0.2"); w.Code != http.StatusOK {
+		t.Errorf("another client = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w := get("/other", "10.0.0.1"); w.Code != http.StatusOK {
+		t.Errorf("same client on a route with its own limiter = %d, want %d", w.Code, http.StatusOK)
+	}
+}
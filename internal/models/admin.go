@@ -6,12 +6,81 @@ import (
 	"gorm.io/gorm"
 )
 
+// Role decides what a user may do, see RolePermissions.
+type Role string
+
+const (
+	RoleSuperAdmin    Role = "super_admin"
+	RoleLeagueAdmin   Role = "league_admin"
+	RoleMatchOfficial Role = "match_official"
+	RoleReadOnly      Role = "read_only"
+)
+
+// Permission is checked per route group.
+type Permission string
+
+const (
+	PermRead          Permission = "read"           // lists, reports and search
+	PermManageLeague  Permission = "manage_league"  // teams, players, seasons, competitions, fixtures and imports
+	PermManageMatches Permission = "manage_matches" // lifecycle, results and lineups of matches
+	PermManageUsers   Permission = "manage_users"   // users and invitations
+)
+
+var RolePermissions = map[Role][]Permission{
+	RoleSuperAdmin:    {PermRead, PermManageLeague, PermManageMatches, PermManageUsers},
+	RoleLeagueAdmin:   {PermRead, PermManageLeague, PermManageMatches},
+	RoleMatchOfficial: {PermRead, PermManageMatches},
+	RoleReadOnly:      {PermRead},
+}
+
+func (r Role) Valid() bool {
+	_, ok := RolePermissions[r]
+	return ok
+}
+
+func (r Role) Can(permission Permission) bool {
+	for _, p := range RolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
 type Admin struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Name      string         `gorm:"size:100;not null" json:"name"`
-	Email     string         `gorm:"size:100;unique;not null" json:"email"`
-	Password  string         `gorm:"size:255;not null" json:"password"` // hashed
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	Email     string    `gorm:"size:100;unique;not null" json:"email"`
+	Password  string    `gorm:"size:255;not null" json:"-"` // hashed
+	Role      Role      `gorm:"size:20" json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SetupLock holds a single row, written along with the first user. Its
+// primary key makes concurrent setups of a fresh install fail instead of
+// creating a super-admin each.
+type SetupLock struct {
+	ID        uint `gorm:"primaryKey;autoIncrement:false"`
+	CreatedAt time.Time
+}
+
+// Invitation lets someone sign up with the given role. Only a hash of the
+// token is stored; the token itself is handed out once, when inviting.
+type Invitation struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Email       string         `gorm:"size:100;not null;index" json:"email"`
+	Role        Role           `gorm:"size:20;not null" json:"role"`
+	TokenHash   string         `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	InvitedByID uint           `json:"invited_by_id"`
+	ExpiresAt   time.Time      `json:"expires_at"`
+	AcceptedAt  *time.Time     `json:"accepted_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// Pending reports whether the invitation can still be accepted.
+func (i Invitation) Pending(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiresAt)
 }
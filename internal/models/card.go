@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CardType string
+
+const (
+	YellowCard       CardType = "yellow"
+	SecondYellowCard CardType = "second_yellow"
+	RedCard          CardType = "red"
+)
+
+// IsDismissal reports whether the card sends the player off.
+func (t CardType) IsDismissal() bool {
+	return t == SecondYellowCard || t == RedCard
+}
+
+// FairPlayPoints is what the card costs its team in the fair play table:
+// 1 for a yellow, 3 for two yellows and 4 for a straight red. The first
+// yellow of a second booking is recorded as a card of its own, so it only
+// adds the difference.
+func (t CardType) FairPlayPoints() int {
+	switch t {
+	case YellowCard:
+		return 1
+	case SecondYellowCard:
+		return 2
+	case RedCard:
+		return 4
+	}
+	return 0
+}
+
+type Card struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	MatchID   uint      `json:"match_id" gorm:"index"`
+	PlayerID  uint      `json:"player_id" gorm:"index"`
+	TeamID    uint      `json:"team_id" gorm:"index"` // team the player was booked for
+	Type      CardType  `json:"type"`
+	Minute    int       `json:"minute"`
+	AddedTime int       `json:"added_time,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Match  Match  `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Player Player `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+func (Card) TableName() string { return "cards" }
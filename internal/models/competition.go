@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CompetitionType string
+
+const (
+	League   CompetitionType = "league"
+	Cup      CompetitionType = "cup"
+	Friendly CompetitionType = "friendly"
+)
+
+type TieBreaker string
+
+const (
+	TieBreakGoalDifference           TieBreaker = "goal_difference"
+	TieBreakGoalsFor                 TieBreaker = "goals_for"
+	TieBreakAwayGoalsFor             TieBreaker = "away_goals_for"
+	TieBreakWins                     TieBreaker = "wins"
+	TieBreakHeadToHeadPoints         TieBreaker = "head_to_head_points"
+	TieBreakHeadToHeadGoalDifference TieBreaker = "head_to_head_goal_difference"
+	TieBreakHeadToHeadGoalsFor       TieBreaker = "head_to_head_goals_for"
+	TieBreakHeadToHeadAwayGoals      TieBreaker = "head_to_head_away_goals"
+	TieBreakFairPlay                 TieBreaker = "fair_play"
+)
+
+// StandingsRules decide how a competition's table is computed. Teams level on
+// points are separated by TieBreakers in order; head-to-head criteria only
+// look at the matches between the teams that are still level.
+type StandingsRules struct {
+	PointsForWin  int          `json:"points_for_win" binding:"min=0"`
+	PointsForDraw int          `json:"points_for_draw" binding:"min=0"`
+	PointsForLoss int          `json:"points_for_loss" binding:"min=0"`
+	TieBreakers   []TieBreaker `json:"tie_breakers" binding:"dive,oneof=goal_difference goals_for away_goals_for wins head_to_head_points head_to_head_goal_difference head_to_head_goals_for head_to_head_away_goals fair_play"`
+}
+
+// DefaultStandingsRules is 3/1/0 with goal difference then goals scored.
+func DefaultStandingsRules() StandingsRules {
+	return StandingsRules{
+		PointsForWin:  3,
+		PointsForDraw: 1,
+		PointsForLoss: 0,
+		TieBreakers:   []TieBreaker{TieBreakGoalDifference, TieBreakGoalsFor},
+	}
+}
+
+// DisciplineRules decide when bookings in a competition lead to a suspension.
+// A player misses the next Matches matches each time their yellow card count
+// reaches one of the YellowCardThresholds.
+type DisciplineRules struct {
+	YellowCardThresholds []YellowCardThreshold `json:"yellow_card_thresholds" binding:"dive"`
+	SecondYellowBan      int                   `json:"second_yellow_ban" binding:"min=0"`
+	RedCardBan           int                   `json:"red_card_ban" binding:"min=0"`
+}
+
+type YellowCardThreshold struct {
+	Yellows int `json:"yellows" binding:"min=1"`
+	Matches int `json:"matches" binding:"min=1"`
+}
+
+// DefaultDisciplineRules bans for one match after 5 yellow cards, two after
+// 10 and three after 15, and for one match after any sending off.
+func DefaultDisciplineRules() DisciplineRules {
+	return DisciplineRules{
+		YellowCardThresholds: []YellowCardThreshold{
+			{Yellows: 5, Matches: 1},
+			{Yellows: 10, Matches: 2},
+			{Yellows: 15, Matches: 3},
+		},
+		SecondYellowBan: 1,
+		RedCardBan:      1,
+	}
+}
+
+type Competition struct {
+	ID         uint             `json:"id" gorm:"primaryKey"`
+	Name       string           `json:"name" binding:"required"`
+	Type       CompetitionType  `json:"type" binding:"required,oneof=league cup friendly" gorm:"default:league"`
+	Rules      *StandingsRules  `json:"rules,omitempty" gorm:"serializer:json"`      // nil means DefaultStandingsRules
+	Discipline *DisciplineRules `json:"discipline,omitempty" gorm:"serializer:json"` // nil means DefaultDisciplineRules
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt   `gorm:"index" json:"deleted_at"`
+}
+
+func (Competition) TableName() string { return "competitions" }
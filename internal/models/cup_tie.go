@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CupTie is one pairing in a knockout bracket. Round 1 is the opening round
+// and the last round is the final. The winner of the tie at Position p moves
+// on to position (p+1)/2 of the next round, as the home side when p is odd.
+type CupTie struct {
+	ID            uint  `json:"id" gorm:"primaryKey"`
+	CompetitionID uint  `json:"competition_id" gorm:"index"`
+	SeasonID      *uint `json:"season_id,omitempty" gorm:"index"`
+	Round         int   `json:"round"`
+	Position      int   `json:"position"`
+	HomeTeamID    *uint `json:"home_team_id,omitempty"` // nil until the previous round is decided
+	AwayTeamID    *uint `json:"away_team_id,omitempty"`
+	HomeSeed      int   `json:"home_seed,omitempty"`
+	AwaySeed      int   `json:"away_seed,omitempty"`
+	TwoLegged     bool  `json:"two_legged"`
+	AwayGoalsRule bool  `json:"away_goals_rule"`
+	WinnerTeamID  *uint `json:"winner_team_id,omitempty"`
+
+	FirstLegAt  time.Time  `json:"first_leg_at"`
+	SecondLegAt *time.Time `json:"second_leg_at,omitempty"`
+
+	HomeTeam *Team   `json:"home_team,omitempty" gorm:"foreignKey:HomeTeamID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	AwayTeam *Team   `json:"away_team,omitempty" gorm:"foreignKey:AwayTeamID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Matches  []Match `json:"matches,omitempty" gorm:"foreignKey:TieID"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+func (CupTie) TableName() string { return "cup_ties" }
@@ -6,12 +6,27 @@ import (
 	"gorm.io/gorm"
 )
 
+type GoalType string
+
+const (
+	OpenPlay GoalType = "open_play"
+	Penalty  GoalType = "penalty"
+	OwnGoal  GoalType = "own_goal"
+	FreeKick GoalType = "free_kick"
+	Header   GoalType = "header"
+)
+
 type Goal struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	MatchID   uint      `json:"match_id" binding:"required"`
-	PlayerID  uint      `json:"player_id" binding:"required"`
-	Minute    int       `json:"minute" binding:"required,min=0,max=130"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                   uint      `json:"id" gorm:"primaryKey"`
+	MatchID              uint      `json:"match_id" binding:"required"`
+	PlayerID             uint      `json:"player_id" binding:"required"`
+	TeamID               uint      `json:"team_id" gorm:"index"` // team the goal counts for, the opponent for an own goal
+	Type                 GoalType  `json:"type" gorm:"default:open_play"`
+	AssistPlayerID       *uint     `json:"assist_player_id,omitempty" gorm:"index"`
+	SecondAssistPlayerID *uint     `json:"second_assist_player_id,omitempty" gorm:"index"`
+	Minute               int       `json:"minute" binding:"required,min=0,max=130"`
+	AddedTime            int       `json:"added_time,omitempty"` // stoppage time, e.g. 2 for 45+2
+	CreatedAt            time.Time `json:"created_at"`
 
 	Match  Match  `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	Player Player `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
@@ -20,3 +35,5 @@ type Goal struct {
 }
 
 func (Goal) TableName() string { return "goals" }
+
+func (g Goal) IsOwnGoal() bool { return g.Type == OwnGoal }
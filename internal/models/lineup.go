@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LineupRole string
+
+const (
+	Starter    LineupRole = "starter"
+	Substitute LineupRole = "substitute"
+)
+
+// LineupEntry is one player named in a team's matchday squad.
+type LineupEntry struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	MatchID     uint           `json:"match_id" gorm:"index"`
+	TeamID      uint           `json:"team_id" gorm:"index"`
+	PlayerID    uint           `json:"player_id" gorm:"index"`
+	Role        LineupRole     `json:"role"`
+	ShirtNumber int            `json:"shirt_number"`
+	Position    PlayerPosition `json:"position"`
+	CreatedAt   time.Time      `json:"created_at"`
+
+	Match  Match  `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Player Player `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+func (LineupEntry) TableName() string { return "lineups" }
+
+type Substitution struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	MatchID     uint      `json:"match_id" gorm:"index"`
+	TeamID      uint      `json:"team_id"`
+	PlayerOffID uint      `json:"player_off_id"`
+	PlayerOnID  uint      `json:"player_on_id"`
+	Minute      int       `json:"minute"`
+	AddedTime   int       `json:"added_time,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	Match     Match  `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	PlayerOff Player `json:"-" gorm:"foreignKey:PlayerOffID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	PlayerOn  Player `json:"-" gorm:"foreignKey:PlayerOnID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+func (Substitution) TableName() string { return "substitutions" }
@@ -10,9 +10,55 @@ type MatchStatus string
 
 const (
 	Scheduled MatchStatus = "scheduled"
+	Live      MatchStatus = "live"
+	HalfTime  MatchStatus = "half_time"
 	Finished  MatchStatus = "finished"
+	Postponed MatchStatus = "postponed"
+	Cancelled MatchStatus = "cancelled"
+	Abandoned MatchStatus = "abandoned"
+	Awarded   MatchStatus = "awarded"
 )
 
+// IsKnown reports whether s is one of the states above.
+func (s MatchStatus) IsKnown() bool {
+	switch s {
+	case Scheduled, Live, HalfTime, Finished, Postponed, Cancelled, Abandoned, Awarded:
+		return true
+	}
+	return false
+}
+
+// matchTransitions lists the states a match may move to from each state.
+// A finished match may be "finished" again when its result is corrected.
+var matchTransitions = map[MatchStatus][]MatchStatus{
+	Scheduled: {Live, Finished, Postponed, Cancelled, Awarded},
+	Live:      {HalfTime, Finished, Abandoned},
+	HalfTime:  {Live, Abandoned},
+	Postponed: {Scheduled, Live, Cancelled, Awarded},
+	Abandoned: {Scheduled, Awarded},
+	Finished:  {Finished, Awarded},
+}
+
+func (s MatchStatus) CanTransitionTo(next MatchStatus) bool {
+	for _, allowed := range matchTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// HasFinalResult reports whether a match in this state has a result that
+// counts in standings and reports.
+func (s MatchStatus) HasFinalResult() bool {
+	return s == Finished || s == Awarded
+}
+
+// IsEditable reports whether the fixture details (teams, kick-off time) may still change.
+func (s MatchStatus) IsEditable() bool {
+	return s == Scheduled || s == Postponed
+}
+
 type Match struct {
 	ID         uint        `json:"id" gorm:"primaryKey"`
 	MatchTime  time.Time   `json:"match_time" binding:"required"` // tanggal + waktu
@@ -22,10 +68,26 @@ type Match struct {
 	AwayScore  *int        `json:"away_score,omitempty"`
 	Status     MatchStatus `json:"status" gorm:"default:scheduled"`
 	Goals      []Goal      `json:"goals,omitempty"`
+	Cards      []Card      `json:"cards,omitempty"`
+
+	SeasonID      *uint `json:"season_id,omitempty" gorm:"index"`
+	CompetitionID *uint `json:"competition_id,omitempty" gorm:"index"`
+	Matchday      int   `json:"matchday,omitempty"` // round number when generated from a fixture list
+
+	// Knockout ties only
+	TieID              *uint `json:"tie_id,omitempty" gorm:"index"`
+	Leg                int   `json:"leg,omitempty"`
+	HomeExtraTimeScore *int  `json:"home_extra_time_score,omitempty"` // extra-time goals, already counted in HomeScore
+	AwayExtraTimeScore *int  `json:"away_extra_time_score,omitempty"`
+	HomePenalties      *int  `json:"home_penalties,omitempty"`
+	AwayPenalties      *int  `json:"away_penalties,omitempty"`
 
 	HomeTeam Team `json:"home_team" gorm:"foreignKey:HomeTeamID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
 	AwayTeam Team `json:"away_team" gorm:"foreignKey:AwayTeamID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
 
+	Season      *Season      `json:"season,omitempty" gorm:"foreignKey:SeasonID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Competition *Competition `json:"competition,omitempty" gorm:"foreignKey:CompetitionID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
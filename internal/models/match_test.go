@@ -0,0 +1,49 @@
+package models
+
+import "testing"
+
+func TestMatchStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to MatchStatus
+		want     bool
+	}{
+		{Scheduled, Live, true},
+		{Scheduled, Finished, true},
+		{Scheduled, Postponed, true},
+		{Scheduled, Cancelled, true},
+		{Scheduled, Awarded, true},
+		{Scheduled, HalfTime, false},
+		{Scheduled, Abandoned, false},
+		{Live, HalfTime, true},
+		{Live, Finished, true},
+		{Live, Abandoned, true},
+		{Live, Scheduled, false},
+		{Live, Awarded, false},
+		{HalfTime, Live, true},
+		{HalfTime, Abandoned, true},
+		{HalfTime, Finished, false},
+		{Postponed, Scheduled, true},
+		{Postponed, Cancelled, true},
+		{Postponed, Awarded, true},
+		{Postponed, Live, true},
+		{Postponed, HalfTime, false},
+		{Abandoned, Scheduled, true},
+		{Abandoned, Awarded, true},
+		{Abandoned, Finished, false},
+		{Finished, Finished, true},
+		{Finished, Awarded, true},
+		{Finished, Scheduled, false},
+		{Finished, Live, false},
+		{Awarded, Finished, false},
+		{Awarded, Scheduled, false},
+		{Cancelled, Scheduled, false},
+		{Cancelled, Awarded, false},
+		{MatchStatus("unknown"), Live, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s -> %s allowed = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
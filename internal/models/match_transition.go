@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// MatchTransition records one status change of a match and who made it.
+type MatchTransition struct {
+	ID         uint        `json:"id" gorm:"primaryKey"`
+	MatchID    uint        `json:"match_id" gorm:"index"`
+	FromStatus MatchStatus `json:"from_status"`
+	ToStatus   MatchStatus `json:"to_status"`
+	AdminID    uint        `json:"admin_id"`
+	Reason     string      `json:"reason,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+
+	Match Match `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (MatchTransition) TableName() string { return "match_transitions" }
@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	"xyz-football/pkg/utils"
+
 	"gorm.io/gorm"
 )
 
@@ -10,24 +12,45 @@ type PlayerPosition string
 
 const (
 	Striker    PlayerPosition = "striker"
-	Midfield   PlayerPosition = "midfield"
+	Midfield   PlayerPosition = "midfielder" // the value the API accepts and stores
 	Defender   PlayerPosition = "defender"
 	Goalkeeper PlayerPosition = "goalkeeper"
 )
 
+// IsKnown reports whether p is one of the positions above.
+func (p PlayerPosition) IsKnown() bool {
+	switch p {
+	case Striker, Midfield, Defender, Goalkeeper:
+		return true
+	}
+	return false
+}
+
 type Player struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	TeamID    uint           `json:"team_id" binding:"required"`
-	Name      string         `json:"name" binding:"required"`
-	HeightCM  float64        `json:"height_cm"`
-	WeightKG  float64        `json:"weight_kg"`
-	Position  PlayerPosition `json:"position" binding:"required,oneof=striker midfielder defender goalkeeper"`
-	Number    int            `json:"number" binding:"required,min=1,max=99"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	TeamID     uint           `json:"team_id" binding:"required"`
+	Name       string         `json:"name" binding:"required"`
+	HeightCM   float64        `json:"height_cm"`
+	WeightKG   float64        `json:"weight_kg"`
+	Position   PlayerPosition `json:"position" binding:"required,oneof=striker midfielder defender goalkeeper"`
+	Number     int            `json:"number" binding:"required,min=1,max=99"`
+	SearchText string         `json:"-" gorm:"index"` // folded name, see SearchKey
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 
 	Team Team `json:"team" gorm:"foreignKey:TeamID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 }
 
 func (Player) TableName() string { return "players" }
+
+// SearchKey is the text a player is found by, folded for accent- and
+// case-insensitive matching.
+func (p Player) SearchKey() string {
+	return utils.Fold(p.Name)
+}
+
+func (p *Player) BeforeSave(tx *gorm.DB) error {
+	p.SearchText = p.SearchKey()
+	return nil
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PlayerContract is a spell of a player at a team. A nil StartDate means the
+// player was with the team since before their history was recorded, a nil
+// EndDate that they still are.
+type PlayerContract struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	PlayerID  uint       `json:"player_id" gorm:"index"`
+	TeamID    uint       `json:"team_id" gorm:"index"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	Player Player `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Team   Team   `json:"team" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+func (PlayerContract) TableName() string { return "player_contracts" }
+
+// ActiveAt reports whether the player was with the team at the given time.
+func (c PlayerContract) ActiveAt(t time.Time) bool {
+	return (c.StartDate == nil || !c.StartDate.After(t)) && (c.EndDate == nil || c.EndDate.After(t))
+}
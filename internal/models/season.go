@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Season struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Name      string         `json:"name" binding:"required"` // e.g. "2025/2026"
+	StartDate time.Time      `json:"start_date" binding:"required"`
+	EndDate   time.Time      `json:"end_date" binding:"required"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+func (Season) TableName() string { return "seasons" }
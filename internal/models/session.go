@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Session is one login of a user. Access tokens carry the session ID, so
+// revoking the session (logging out) revokes them along with its refresh
+// tokens, without waiting for them to expire.
+type Session struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	AdminID   uint       `json:"admin_id" gorm:"not null;index"`
+	ExpiresAt time.Time  `json:"expires_at"` // when the latest refresh token runs out
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	Admin Admin `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (Session) TableName() string { return "sessions" }
+
+// Active reports whether the session can still be used.
+func (s Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// RefreshToken is one refresh token of a session. Each is good for a single
+// refresh, which hands out the next one; only a hash of the token is stored.
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	SessionID uint       `json:"session_id" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+
+	Session Session `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (RefreshToken) TableName() string { return "refresh_tokens" }
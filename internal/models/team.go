@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	"xyz-football/pkg/utils"
+
 	"gorm.io/gorm"
 )
 
@@ -13,6 +15,7 @@ type Team struct {
 	FoundedYear int            `json:"founded_year"`
 	StadiumAddr string         `json:"stadium_address"`
 	City        string         `json:"city"`
+	SearchText  string         `json:"-" gorm:"index"` // folded name, city and stadium, see SearchKey
 	Players     []Player       `json:"players,omitempty"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
@@ -20,3 +23,14 @@ type Team struct {
 }
 
 func (Team) TableName() string { return "teams" }
+
+// SearchKey is the text a team is found by, folded for accent- and
+// case-insensitive matching.
+func (t Team) SearchKey() string {
+	return utils.Fold(t.Name + " " + t.City + " " + t.StadiumAddr)
+}
+
+func (t *Team) BeforeSave(tx *gorm.DB) error {
+	t.SearchText = t.SearchKey()
+	return nil
+}
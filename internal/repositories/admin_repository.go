@@ -1,14 +1,33 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
 	"xyz-football/internal/models"
 
 	"gorm.io/gorm"
 )
 
+// ErrSetupDone is returned by CreateFirst once there is a user.
+var ErrSetupDone = errors.New("setup is already done")
+
 type AdminRepository interface {
 	FindByEmail(email string) (*models.Admin, error)
+	FindByID(id uint) (*models.Admin, error)
+	FindAll() ([]models.Admin, error)
+	CountByRole(role models.Role) (int64, error)
 	Create(admin *models.Admin) error
+	CreateFirst(admin *models.Admin) error
+	Update(admin *models.Admin) error
+	Delete(id uint) error
+
+	CreateInvitation(invitation *models.Invitation) error
+	FindInvitationByID(id uint) (*models.Invitation, error)
+	FindInvitationByTokenHash(hash string) (*models.Invitation, error)
+	FindPendingInvitations(now time.Time) ([]models.Invitation, error)
+	DeleteInvitation(id uint) error
+	AcceptInvitation(invitation *models.Invitation, admin *models.Admin) error
 }
 
 type adminRepository struct {
@@ -19,15 +38,119 @@ func NewAdminRepository(db *gorm.DB) AdminRepository {
 	return &adminRepository{db: db}
 }
 
+// FindByEmail takes a lower-cased email; accounts created before emails
+// were lower-cased on the way in are matched all the same.
 func (r *adminRepository) FindByEmail(email string) (*models.Admin, error) {
 	var admin models.Admin
-	err := r.db.Where("email = ?", email).First(&admin).Error
+	err := r.db.Where("LOWER(email) = ?", email).First(&admin).Error
 	if err != nil {
 		return nil, err
 	}
 	return &admin, nil
 }
 
+func (r *adminRepository) FindByID(id uint) (*models.Admin, error) {
+	var admin models.Admin
+	if err := r.db.First(&admin, id).Error; err != nil {
+		return nil, err
+	}
+	return &admin, nil
+}
+
+func (r *adminRepository) FindAll() ([]models.Admin, error) {
+	var admins []models.Admin
+	err := r.db.Order("id ASC").Find(&admins).Error
+	return admins, err
+}
+
+func (r *adminRepository) CountByRole(role models.Role) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Admin{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
 func (r *adminRepository) Create(admin *models.Admin) error {
 	return r.db.Create(admin).Error
 }
+
+// CreateFirst creates the admin only while there are no users at all. The
+// setup lock is taken first, so of two setups at the same time one fails on
+// it; installs set up before the lock existed are caught by the count.
+func (r *adminRepository) CreateFirst(admin *models.Admin) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&models.SetupLock{ID: 1}).Error; err != nil {
+			return ErrSetupDone
+		}
+
+		var count int64
+		if err := tx.Model(&models.Admin{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrSetupDone
+		}
+		return tx.Create(admin).Error
+	})
+}
+
+func (r *adminRepository) Update(admin *models.Admin) error {
+	return r.db.Save(admin).Error
+}
+
+// Delete removes the user for good, so their email can be invited again.
+func (r *adminRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Admin{}, id).Error
+}
+
+func (r *adminRepository) CreateInvitation(invitation *models.Invitation) error {
+	return r.db.Create(invitation).Error
+}
+
+func (r *adminRepository) FindInvitationByID(id uint) (*models.Invitation, error) {
+	var invitation models.Invitation
+	if err := r.db.First(&invitation, id).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *adminRepository) FindInvitationByTokenHash(hash string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	if err := r.db.Where("token_hash = ?", hash).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *adminRepository) FindPendingInvitations(now time.Time) ([]models.Invitation, error) {
+	var invitations []models.Invitation
+	err := r.db.
+		Where("accepted_at IS NULL AND expires_at > ?", now).
+		Order("created_at ASC, id ASC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *adminRepository) DeleteInvitation(id uint) error {
+	return r.db.Delete(&models.Invitation{}, id).Error
+}
+
+// AcceptInvitation creates the invited user and marks the invitation used in
+// one go, so an invitation can never be accepted twice.
+func (r *adminRepository) AcceptInvitation(invitation *models.Invitation, admin *models.Admin) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.Invitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Update("accepted_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		invitation.AcceptedAt = &now
+
+		return tx.Create(admin).Error
+	})
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"xyz-football/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type BracketRepository interface {
+	CreateTie(tie *models.CupTie) error
+	FindTieByID(id uint) (*models.CupTie, error)
+	FindTie(competitionID uint, seasonID *uint, round, position int) (*models.CupTie, error)
+	FindByCompetition(competitionID uint, seasonID *uint) ([]models.CupTie, error)
+	UpdateTie(tie *models.CupTie) error
+}
+
+type bracketRepository struct {
+	db *gorm.DB
+}
+
+func NewBracketRepository(db *gorm.DB) BracketRepository {
+	return &bracketRepository{db: db}
+}
+
+func (r *bracketRepository) CreateTie(tie *models.CupTie) error {
+	return r.db.Create(tie).Error
+}
+
+func (r *bracketRepository) FindTieByID(id uint) (*models.CupTie, error) {
+	var tie models.CupTie
+	err := r.db.
+		Preload("Matches", func(db *gorm.DB) *gorm.DB { return db.Order("leg ASC") }).
+		First(&tie, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tie, nil
+}
+
+func (r *bracketRepository) FindTie(competitionID uint, seasonID *uint, round, position int) (*models.CupTie, error) {
+	var tie models.CupTie
+	err := r.scoped(competitionID, seasonID).
+		Preload("Matches", func(db *gorm.DB) *gorm.DB { return db.Order("leg ASC") }).
+		Where("round = ? AND position = ?", round, position).
+		First(&tie).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tie, nil
+}
+
+func (r *bracketRepository) FindByCompetition(competitionID uint, seasonID *uint) ([]models.CupTie, error) {
+	var ties []models.CupTie
+	err := r.scoped(competitionID, seasonID).
+		Preload("HomeTeam").
+		Preload("AwayTeam").
+		Preload("Matches", func(db *gorm.DB) *gorm.DB { return db.Order("leg ASC") }).
+		Order("round ASC, position ASC").
+		Find(&ties).Error
+	return ties, err
+}
+
+func (r *bracketRepository) UpdateTie(tie *models.CupTie) error {
+	return r.db.Omit("HomeTeam", "AwayTeam", "Matches").Save(tie).Error
+}
+
+// scoped limits a query to one competition's bracket for a season
+// (or the season-less bracket when seasonID is nil).
+func (r *bracketRepository) scoped(competitionID uint, seasonID *uint) *gorm.DB {
+	query := r.db.Where("competition_id = ?", competitionID)
+	if seasonID != nil {
+		return query.Where("season_id = ?", *seasonID)
+	}
+	return query.Where("season_id IS NULL")
+}
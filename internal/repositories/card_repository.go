@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"xyz-football/internal/models"
+)
+
+// CardFilter narrows bookings down to the matches of a MatchFilter and
+// optionally to one player or team.
+type CardFilter struct {
+	MatchFilter
+	PlayerID *uint
+	TeamID   *uint
+}
+
+type CardRepository interface {
+	FindByFilter(filter CardFilter) ([]models.Card, error)
+}
+
+type cardRepository struct {
+	db *gorm.DB
+}
+
+func NewCardRepository(db *gorm.DB) CardRepository {
+	return &cardRepository{db: db}
+}
+
+// FindByFilter returns the bookings in chronological order, with their match
+// (teams and competition) and player loaded.
+func (r *cardRepository) FindByFilter(filter CardFilter) ([]models.Card, error) {
+	query := r.db.
+		Preload("Match.HomeTeam").
+		Preload("Match.AwayTeam").
+		Preload("Match.Competition").
+		Preload("Player").
+		Joins("JOIN matches ON matches.id = cards.match_id AND matches.deleted_at IS NULL").
+		Scopes(ScopeMatches(filter.MatchFilter))
+
+	if filter.PlayerID != nil {
+		query = query.Where("cards.player_id = ?", *filter.PlayerID)
+	}
+	if filter.TeamID != nil {
+		query = query.Where("cards.team_id = ?", *filter.TeamID)
+	}
+
+	var cards []models.Card
+	err := query.
+		Order("matches.match_time ASC, cards.minute ASC, cards.added_time ASC").
+		Find(&cards).Error
+	return cards, err
+}
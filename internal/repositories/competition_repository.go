@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"xyz-football/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type CompetitionRepository interface {
+	Create(competition *models.Competition) error
+	FindAll() ([]models.Competition, error)
+	FindByID(id uint) (*models.Competition, error)
+	Update(competition *models.Competition) error
+	Delete(id uint) error
+}
+
+type competitionRepository struct {
+	db *gorm.DB
+}
+
+func NewCompetitionRepository(db *gorm.DB) CompetitionRepository {
+	return &competitionRepository{db: db}
+}
+
+func (r *competitionRepository) Create(competition *models.Competition) error {
+	return r.db.Create(competition).Error
+}
+
+func (r *competitionRepository) FindAll() ([]models.Competition, error) {
+	var competitions []models.Competition
+	err := r.db.Order("name ASC").Find(&competitions).Error
+	return competitions, err
+}
+
+func (r *competitionRepository) FindByID(id uint) (*models.Competition, error) {
+	var competition models.Competition
+	err := r.db.First(&competition, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &competition, nil
+}
+
+func (r *competitionRepository) Update(competition *models.Competition) error {
+	// check is exist
+	if _, err := r.FindByID(competition.ID); err != nil {
+		return err
+	}
+	return r.db.Save(competition).Error
+}
+
+func (r *competitionRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Competition{}, id).Error
+}
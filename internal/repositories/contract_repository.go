@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"xyz-football/internal/models"
+)
+
+type ContractRepository interface {
+	FindByPlayer(playerID uint) ([]models.PlayerContract, error)
+	FindTeamsAt(playerIDs []uint, at time.Time) (map[uint]uint, error)
+	FindSquadAt(teamID uint, at time.Time) ([]models.Player, error)
+	FindLastAppearance(playerID uint, until time.Time) (*models.Match, error)
+	Transfer(player *models.Player, toTeamID uint, number int, at time.Time) error
+}
+
+type contractRepository struct {
+	db *gorm.DB
+}
+
+func NewContractRepository(db *gorm.DB) ContractRepository {
+	return &contractRepository{db: db}
+}
+
+// activeAt matches the contracts running at the given time.
+func activeAt(at time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Where("(player_contracts.start_date IS NULL OR player_contracts.start_date <= ?)", at).
+			Where("(player_contracts.end_date IS NULL OR player_contracts.end_date > ?)", at)
+	}
+}
+
+func (r *contractRepository) FindByPlayer(playerID uint) ([]models.PlayerContract, error) {
+	var contracts []models.PlayerContract
+	err := r.db.
+		Preload("Team").
+		Where("player_id = ?", playerID).
+		Order("start_date IS NOT NULL, start_date ASC, id ASC").
+		Find(&contracts).Error
+	return contracts, err
+}
+
+// FindTeamsAt returns the team each player was with at the given time.
+// Players without a contract running then are left out.
+func (r *contractRepository) FindTeamsAt(playerIDs []uint, at time.Time) (map[uint]uint, error) {
+	teams := make(map[uint]uint, len(playerIDs))
+	if len(playerIDs) == 0 {
+		return teams, nil
+	}
+
+	var contracts []models.PlayerContract
+	err := r.db.
+		Scopes(activeAt(at)).
+		Where("player_id IN ?", playerIDs).
+		Find(&contracts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, contract := range contracts {
+		teams[contract.PlayerID] = contract.TeamID
+	}
+	return teams, nil
+}
+
+// FindSquadAt returns the players registered with the team at the given time.
+func (r *contractRepository) FindSquadAt(teamID uint, at time.Time) ([]models.Player, error) {
+	var players []models.Player
+	err := r.db.
+		Joins("JOIN player_contracts ON player_contracts.player_id = players.id AND player_contracts.deleted_at IS NULL").
+		Scopes(activeAt(at)).
+		Where("player_contracts.team_id = ?", teamID).
+		Find(&players).Error
+	return players, err
+}
+
+// FindLastAppearance returns the latest match up to the given time the player
+// took part in, named in a lineup or down for a goal, assist or card. It
+// returns gorm.ErrRecordNotFound for a player who has not played yet.
+func (r *contractRepository) FindLastAppearance(playerID uint, until time.Time) (*models.Match, error) {
+	lineups := r.db.Model(&models.LineupEntry{}).Select("match_id").Where("player_id = ?", playerID)
+	goals := r.db.Model(&models.Goal{}).Select("match_id").
+		Where("player_id = ? OR assist_player_id = ? OR second_assist_player_id = ?", playerID, playerID, playerID)
+	cards := r.db.Model(&models.Card{}).Select("match_id").Where("player_id = ?", playerID)
+
+	var match models.Match
+	err := r.db.
+		Where("id IN (?) OR id IN (?) OR id IN (?)", lineups, goals, cards).
+		Where("match_time <= ?", until).
+		Order("match_time DESC").
+		First(&match).Error
+	if err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// Transfer ends the player's current contract at the given time, starts one
+// at the new team and moves the player there, all in a single transaction.
+func (r *contractRepository) Transfer(player *models.Player, toTeamID uint, number int, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.PlayerContract{}).
+			Where("player_id = ? AND end_date IS NULL", player.ID).
+			Update("end_date", at).Error
+		if err != nil {
+			return err
+		}
+
+		contract := &models.PlayerContract{PlayerID: player.ID, TeamID: toTeamID, StartDate: &at}
+		if err := tx.Create(contract).Error; err != nil {
+			return err
+		}
+
+		err = tx.Model(&models.Player{}).
+			Where("id = ?", player.ID).
+			Updates(map[string]interface{}{"team_id": toTeamID, "number": number}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Preload("Team").First(player, player.ID).Error
+	})
+}
@@ -9,7 +9,8 @@ type GoalRepository interface {
 	Create(goal *models.Goal) error
 	FindByID(id uint) (*models.Goal, error)
 	FindByMatch(matchID uint) ([]models.Goal, error)
-	FindByPlayer(playerID uint) ([]models.Goal, error)
+	FindByPlayer(playerID uint, filter MatchFilter) ([]models.Goal, error)
+	FindAssistsByPlayer(playerID uint, filter MatchFilter) ([]models.Goal, error)
 	FindByTeam(teamID uint) ([]models.Goal, error)
 	Update(goal *models.Goal) error
 	Delete(id uint) error
@@ -46,15 +47,34 @@ func (r *goalRepository) FindByMatch(matchID uint) ([]models.Goal, error) {
 	return goals, err
 }
 
-func (r *goalRepository) FindByPlayer(playerID uint) ([]models.Goal, error) {
+// FindByPlayer returns the goals a player scored in the matches of the
+// filter, own goals included, in chronological order.
+func (r *goalRepository) FindByPlayer(playerID uint, filter MatchFilter) ([]models.Goal, error) {
 	var goals []models.Goal
-	err := r.db.
-		Preload("Match").
-		Where("player_id = ?", playerID).
+	err := r.inMatches(filter).
+		Where("goals.player_id = ?", playerID).
+		Find(&goals).Error
+	return goals, err
+}
+
+// FindAssistsByPlayer returns the goals a player assisted, first or second,
+// in the matches of the filter.
+func (r *goalRepository) FindAssistsByPlayer(playerID uint, filter MatchFilter) ([]models.Goal, error) {
+	var goals []models.Goal
+	err := r.inMatches(filter).
+		Where("(goals.assist_player_id = ? OR goals.second_assist_player_id = ?)", playerID, playerID).
 		Find(&goals).Error
 	return goals, err
 }
 
+func (r *goalRepository) inMatches(filter MatchFilter) *gorm.DB {
+	return r.db.
+		Preload("Match").
+		Joins("JOIN matches ON matches.id = goals.match_id AND matches.deleted_at IS NULL").
+		Scopes(ScopeMatches(filter)).
+		Order("matches.match_time ASC, goals.minute ASC, goals.added_time ASC")
+}
+
 func (r *goalRepository) FindByTeam(teamID uint) ([]models.Goal, error) {
 	var goals []models.Goal
 	err := r.db.
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"xyz-football/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ImportRepository saves a whole import batch in one transaction, so either
+// every row is stored or none is.
+type ImportRepository interface {
+	CreateTeams(teams []models.Team) error
+	CreatePlayers(players []models.Player) error
+	CreateMatches(matches []models.Match) error
+}
+
+type importRepository struct {
+	db *gorm.DB
+}
+
+func NewImportRepository(db *gorm.DB) ImportRepository {
+	return &importRepository{db: db}
+}
+
+func (r *importRepository) CreateTeams(teams []models.Team) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range teams {
+			if err := tx.Create(&teams[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *importRepository) CreatePlayers(players []models.Player) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range players {
+			if err := tx.Create(&players[i]).Error; err != nil {
+				return err
+			}
+			// Every player starts with an open-ended contract at their team
+			contract := models.PlayerContract{PlayerID: players[i].ID, TeamID: players[i].TeamID}
+			if err := tx.Create(&contract).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *importRepository) CreateMatches(matches []models.Match) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range matches {
+			if err := tx.Create(&matches[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
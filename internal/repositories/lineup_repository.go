@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"xyz-football/internal/models"
+)
+
+type LineupRepository interface {
+	ReplaceLineup(matchID, teamID uint, entries []models.LineupEntry) error
+	FindByMatch(matchID uint) ([]models.LineupEntry, error)
+	FindByFilter(filter MatchFilter) ([]models.LineupEntry, error)
+	CreateSubstitution(sub *models.Substitution) error
+	DeleteSubstitution(id uint) error
+	FindSubstitutions(matchID uint) ([]models.Substitution, error)
+	FindSubstitutionsByFilter(filter MatchFilter) ([]models.Substitution, error)
+}
+
+type lineupRepository struct {
+	db *gorm.DB
+}
+
+func NewLineupRepository(db *gorm.DB) LineupRepository {
+	return &lineupRepository{db: db}
+}
+
+// ReplaceLineup swaps a team's matchday squad for a new one in a single transaction.
+func (r *lineupRepository) ReplaceLineup(matchID, teamID uint, entries []models.LineupEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("match_id = ? AND team_id = ?", matchID, teamID).
+			Delete(&models.LineupEntry{}).Error
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.Create(&entries).Error
+	})
+}
+
+func (r *lineupRepository) FindByMatch(matchID uint) ([]models.LineupEntry, error) {
+	var entries []models.LineupEntry
+	err := r.db.
+		Preload("Player").
+		Where("match_id = ?", matchID).
+		Order("id ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *lineupRepository) FindByFilter(filter MatchFilter) ([]models.LineupEntry, error) {
+	var entries []models.LineupEntry
+	err := r.db.
+		Joins("JOIN matches ON matches.id = lineups.match_id AND matches.deleted_at IS NULL").
+		Scopes(ScopeMatches(filter)).
+		Order("lineups.id ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *lineupRepository) CreateSubstitution(sub *models.Substitution) error {
+	return r.db.Create(sub).Error
+}
+
+func (r *lineupRepository) DeleteSubstitution(id uint) error {
+	return r.db.Delete(&models.Substitution{}, id).Error
+}
+
+func (r *lineupRepository) FindSubstitutions(matchID uint) ([]models.Substitution, error) {
+	var subs []models.Substitution
+	err := r.db.
+		Where("match_id = ?", matchID).
+		Order("minute ASC, added_time ASC, id ASC").
+		Find(&subs).Error
+	return subs, err
+}
+
+func (r *lineupRepository) FindSubstitutionsByFilter(filter MatchFilter) ([]models.Substitution, error) {
+	var subs []models.Substitution
+	err := r.db.
+		Joins("JOIN matches ON matches.id = substitutions.match_id AND matches.deleted_at IS NULL").
+		Scopes(ScopeMatches(filter)).
+		Order("substitutions.minute ASC, substitutions.added_time ASC, substitutions.id ASC").
+		Find(&subs).Error
+	return subs, err
+}
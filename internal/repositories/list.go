@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidSort is returned for a sort field a list does not support.
+var ErrInvalidSort = errors.New("invalid sort field")
+
+// ListOptions selects one page of a list and its order. Sort is a comma
+// separated list of fields, each prefixed with "-" for descending order,
+// e.g. "-match_time,id".
+type ListOptions struct {
+	Page    int
+	PerPage int
+	Sort    string
+}
+
+// paginate counts the rows matching the query and loads the requested page
+// into dest. sortable maps the sort fields a list accepts to their columns;
+// rows are ordered by defaultSort when none is given, and by id last so that
+// pages stay stable.
+func paginate(query *gorm.DB, opts ListOptions, sortable map[string]string, defaultSort string, dest interface{}, preloads ...string) (int64, error) {
+	sort := opts.Sort
+	if sort == "" {
+		sort = defaultSort
+	}
+	order, err := orderBy(sort, sortable)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return 0, err
+	}
+
+	find := query.Session(&gorm.Session{})
+	for _, preload := range preloads {
+		find = find.Preload(preload)
+	}
+	err = find.
+		Order(order).
+		Offset((opts.Page - 1) * opts.PerPage).
+		Limit(opts.PerPage).
+		Find(dest).Error
+	return total, err
+}
+
+func orderBy(sort string, sortable map[string]string) (string, error) {
+	var terms []string
+	byID := false
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		direction := "ASC"
+		if strings.HasPrefix(field, "-") {
+			field, direction = field[1:], "DESC"
+		}
+
+		column, ok := sortable[field]
+		if !ok {
+			return "", fmt.Errorf("%w %q", ErrInvalidSort, field)
+		}
+		byID = byID || field == "id"
+		terms = append(terms, column+" "+direction)
+	}
+	if !byID {
+		terms = append(terms, sortable["id"]+" ASC")
+	}
+	return strings.Join(terms, ", "), nil
+}
+
+// searchTerms narrows a query down to rows whose search_text contains every
+// one of the already folded terms.
+func searchTerms(query *gorm.DB, column string, terms []string) *gorm.DB {
+	for _, term := range terms {
+		query = query.Where(column+" LIKE ? ESCAPE '!'", containsPattern(term))
+	}
+	return query
+}
+
+// containsPattern builds a lower-case LIKE pattern matching the text anywhere,
+// to be used with "... LIKE ? ESCAPE '!'" against lower-case text. Raw columns
+// need LOWER(column); search_text is stored folded, so searchTerms compares it
+// as is and LOWER() there would only keep its index from being used.
+func containsPattern(text string) string {
+	escaped := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(strings.ToLower(text))
+	return "%" + escaped + "%"
+}
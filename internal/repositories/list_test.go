@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"xyz-football/internal/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestOrderBy(t *testing.T) {
+	sortable := map[string]string{"id": "teams.id", "name": "teams.name", "city": "teams.city"}
+
+	tests := []struct {
+		name    string
+		sort    string
+		want    string
+		wantErr bool
+	}{
+		{"ascending", "name", "teams.name ASC, teams.id ASC", false},
+		{"descending", "-name", "teams.name DESC, teams.id ASC", false},
+		{"several fields", "city, -name", "teams.city ASC, teams.name DESC, teams.id ASC", false},
+		{"id given", "-id", "teams.id DESC", false},
+		{"id given after another field", "name,-id", "teams.name ASC, teams.id DESC", false},
+		{"unknown field", "stadium", "", true},
+		{"column instead of field", "teams.name", "", true},
+		{"empty field", "name,", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := orderBy(tt.sort, sortable)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSort) {
+					t.Errorf("orderBy(%q) error = %v, want %v", tt.sort, err, ErrInvalidSort)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("orderBy(%q) error = %v", tt.sort, err)
+			}
+			if got != tt.want {
+				t.Errorf("orderBy(%q) = %q, want %q", tt.sort, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // every connection would get a database of its own
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&models.Team{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two cities with two teams each, so sorting by city alone leaves ties
+	for _, team := range []models.Team{
+		{Name: "Delta", City: "North"},
+		{Name: "Alpha", City: "South"},
+		{Name: "Charlie", City: "North"},
+		{Name: "Bravo", City: "South"},
+		{Name: "Echo", City: "East"},
+	} {
+		if err := db.Create(&team).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts ListOptions
+		want []string
+	}{
+		{"default sort", ListOptions{Page: 1, PerPage: 2}, []string{"Alpha", "Bravo"}},
+		{"second page", ListOptions{Page: 2, PerPage: 2}, []string{"Charlie", "Delta"}},
+		{"last page", ListOptions{Page: 3, PerPage: 2}, []string{"Echo"}},
+		{"past the end", ListOptions{Page: 4, PerPage: 2}, []string{}},
+		{"ties broken by id", ListOptions{Page: 1, PerPage: 5, Sort: "-city"}, []string{"Alpha", "Bravo", "Delta", "Charlie", "Echo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var teams []models.Team
+			total, err := paginate(db.Model(&models.Team{}), tt.opts, teamSortable, "name", &teams)
+			if err != nil {
+				t.Fatalf("paginate() error = %v", err)
+			}
+			if total != 5 {
+				t.Errorf("total = %d, want 5", total)
+			}
+
+			got := make([]string, len(teams))
+			for i, team := range teams {
+				got[i] = team.Name
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("page = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("page = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	t.Run("unknown sort field", func(t *testing.T) {
+		var teams []models.Team
+		_, err := paginate(db.Model(&models.Team{}), ListOptions{Page: 1, PerPage: 2, Sort: "stadium"}, teamSortable, "name", &teams)
+		if !errors.Is(err, ErrInvalidSort) {
+			t.Errorf("paginate() error = %v, want %v", err, ErrInvalidSort)
+		}
+	})
+}
@@ -4,17 +4,73 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"xyz-football/internal/models"
 )
 
+// MatchFilter narrows a match query down to a season and/or competition.
+type MatchFilter struct {
+	SeasonID          *uint
+	CompetitionID     *uint
+	ExcludeFriendlies bool
+}
+
+// ScopeMatches applies a MatchFilter to any query that has the matches table
+// in scope, so raw report queries joining matches can share the same rules.
+func ScopeMatches(filter MatchFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.SeasonID != nil {
+			db = db.Where("matches.season_id = ?", *filter.SeasonID)
+		}
+		if filter.CompetitionID != nil {
+			db = db.Where("matches.competition_id = ?", *filter.CompetitionID)
+		}
+		if filter.ExcludeFriendlies {
+			friendlies := db.Session(&gorm.Session{NewDB: true}).
+				Unscoped().
+				Model(&models.Competition{}).
+				Select("id").
+				Where("type = ?", models.Friendly)
+			db = db.Where("(matches.competition_id IS NULL OR matches.competition_id NOT IN (?))", friendlies)
+		}
+		return db
+	}
+}
+
+// MatchListFilter narrows the match list down. TeamID matches either side;
+// From and To bound the kick-off time, both inclusive.
+type MatchListFilter struct {
+	MatchFilter
+	TeamID *uint
+	Status models.MatchStatus
+	From   *time.Time
+	To     *time.Time
+}
+
+// CumulativeWins is the number of wins a team had collected by the kick-off
+// of a match, that match included.
+type CumulativeWins struct {
+	MatchID uint
+	TeamID  uint
+	Wins    int
+}
+
 type MatchRepository interface {
 	Create(match *models.Match) error
 	FindAll() ([]models.Match, error)
+	List(filter MatchListFilter, opts ListOptions) ([]models.Match, int64, error)
+	FindByFilter(filter MatchFilter) ([]models.Match, error)
 	FindByID(id uint) (*models.Match, error)
+	FindDetailedByFilter(filter MatchFilter) ([]models.Match, error)
+	FindCumulativeWins(filter MatchFilter) ([]CumulativeWins, error)
 	FindByDateRange(start, end time.Time) ([]models.Match, error)
 	FindByTeamID(teamID uint) ([]models.Match, error)
+	FindBetweenTeams(teamA, teamB uint, filter MatchFilter) ([]models.Match, error)
 	Update(match *models.Match) error
 	Delete(id uint) error
+	CreateTransition(transition *models.MatchTransition) error
+	FindTransitions(matchID uint) ([]models.MatchTransition, error)
+	FindResultTimes(matchIDs []uint) (map[uint]time.Time, error)
 	WithTransaction(txFunc func(repo MatchRepository) error) error
 	GetDB() *gorm.DB
 }
@@ -36,6 +92,8 @@ func (r *matchRepository) Create(match *models.Match) error {
 		Preload("HomeTeam").
 		Preload("AwayTeam").
 		Preload("Goals").
+		Preload("Season").
+		Preload("Competition").
 		First(match, match.ID).Error
 }
 
@@ -50,12 +108,62 @@ func (r *matchRepository) FindAll() ([]models.Match, error) {
 	return matches, err
 }
 
+var matchSortable = map[string]string{
+	"id":         "id",
+	"match_time": "match_time",
+	"status":     "status",
+	"matchday":   "matchday",
+	"created_at": "created_at",
+}
+
+func (r *matchRepository) List(filter MatchListFilter, opts ListOptions) ([]models.Match, int64, error) {
+	query := r.db.Model(&models.Match{}).Scopes(ScopeMatches(filter.MatchFilter))
+	if filter.TeamID != nil {
+		query = query.Where("(home_team_id = ? OR away_team_id = ?)", *filter.TeamID, *filter.TeamID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("match_time >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("match_time <= ?", *filter.To)
+	}
+
+	var matches []models.Match
+	total, err := paginate(query, opts, matchSortable, "match_time", &matches, "HomeTeam", "AwayTeam")
+	return matches, total, err
+}
+
+func (r *matchRepository) FindByFilter(filter MatchFilter) ([]models.Match, error) {
+	var matches []models.Match
+	err := r.db.
+		Preload("HomeTeam").
+		Preload("AwayTeam").
+		Preload("Goals").
+		Preload("Cards").
+		Scopes(ScopeMatches(filter)).
+		Order("match_time ASC").
+		Find(&matches).Error
+	return matches, err
+}
+
 func (r *matchRepository) FindByID(id uint) (*models.Match, error) {
 	var match models.Match
 	err := r.db.
 		Preload("HomeTeam").
 		Preload("AwayTeam").
-		Preload("Goals").
+		Preload("Goals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("minute ASC, added_time ASC")
+		}).
+		Preload("Goals.Player").
+		Preload("Cards", func(db *gorm.DB) *gorm.DB {
+			return db.Order("minute ASC, added_time ASC")
+		}).
+		Preload("Cards.Player").
+		Preload("Season").
+		Preload("Competition").
 		First(&match, id).Error
 	if err != nil {
 		return nil, err
@@ -63,6 +171,53 @@ func (r *matchRepository) FindByID(id uint) (*models.Match, error) {
 	return &match, nil
 }
 
+// FindDetailedByFilter loads the matches of a filter with everything
+// FindByID loads, for reports that list several matches at once.
+func (r *matchRepository) FindDetailedByFilter(filter MatchFilter) ([]models.Match, error) {
+	var matches []models.Match
+	err := r.db.
+		Preload("HomeTeam").
+		Preload("AwayTeam").
+		Preload("Goals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("minute ASC, added_time ASC")
+		}).
+		Preload("Goals.Player").
+		Preload("Cards", func(db *gorm.DB) *gorm.DB {
+			return db.Order("minute ASC, added_time ASC")
+		}).
+		Preload("Cards.Player").
+		Preload("Season").
+		Preload("Competition").
+		Scopes(ScopeMatches(filter)).
+		Order("match_time ASC, id ASC").
+		Find(&matches).Error
+	return matches, err
+}
+
+// FindCumulativeWins returns, for both teams of every match in the filter,
+// how many matches with a final result the team had won up to and including
+// that kick-off. It is a single query: each match is split into a home and an
+// away row and the wins are summed with a window over each team's rows.
+func (r *matchRepository) FindCumulativeWins(filter MatchFilter) ([]CumulativeWins, error) {
+	final := []models.MatchStatus{models.Finished, models.Awarded}
+	side := func(team, scored, conceded string) *gorm.DB {
+		return r.db.Table("matches").
+			Select("matches.id AS match_id, matches.match_time AS match_time, matches."+team+" AS team_id, "+
+				"CASE WHEN matches.status IN ? AND matches."+scored+" > matches."+conceded+" THEN 1 ELSE 0 END AS won", final).
+			Where("matches.deleted_at IS NULL").
+			Scopes(ScopeMatches(filter))
+	}
+
+	var rows []CumulativeWins
+	err := r.db.Raw(
+		"SELECT match_id, team_id, SUM(won) OVER (PARTITION BY team_id ORDER BY match_time) AS wins"+
+			" FROM (? UNION ALL ?) AS sides",
+		side("home_team_id", "home_score", "away_score"),
+		side("away_team_id", "away_score", "home_score"),
+	).Scan(&rows).Error
+	return rows, err
+}
+
 func (r *matchRepository) FindByDateRange(start, end time.Time) ([]models.Match, error) {
 	var matches []models.Match
 	err := r.db.
@@ -85,8 +240,29 @@ func (r *matchRepository) FindByTeamID(teamID uint) ([]models.Match, error) {
 	return matches, err
 }
 
+// FindBetweenTeams returns the meetings of two teams, whoever was at home,
+// with their goals and scorers loaded.
+func (r *matchRepository) FindBetweenTeams(teamA, teamB uint, filter MatchFilter) ([]models.Match, error) {
+	var matches []models.Match
+	err := r.db.
+		Preload("HomeTeam").
+		Preload("AwayTeam").
+		Preload("Competition").
+		Preload("Goals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("minute ASC, added_time ASC")
+		}).
+		Preload("Goals.Player").
+		Where("(home_team_id = ? AND away_team_id = ?) OR (home_team_id = ? AND away_team_id = ?)", teamA, teamB, teamB, teamA).
+		Scopes(ScopeMatches(filter)).
+		Order("match_time ASC").
+		Find(&matches).Error
+	return matches, err
+}
+
 func (r *matchRepository) Update(match *models.Match) error {
-	if err := r.db.Save(match).Error; err != nil {
+	// Associations are managed separately; saving preloaded teams would
+	// overwrite changed foreign keys with the old team IDs
+	if err := r.db.Omit(clause.Associations).Save(match).Error; err != nil {
 		return err
 	}
 	// Fetch the updated match with all relationships
@@ -94,6 +270,8 @@ func (r *matchRepository) Update(match *models.Match) error {
 		Preload("HomeTeam").
 		Preload("AwayTeam").
 		Preload("Goals").
+		Preload("Season").
+		Preload("Competition").
 		First(match, match.ID).Error
 }
 
@@ -101,6 +279,45 @@ func (r *matchRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Match{}, id).Error
 }
 
+func (r *matchRepository) CreateTransition(transition *models.MatchTransition) error {
+	return r.db.Create(transition).Error
+}
+
+func (r *matchRepository) FindTransitions(matchID uint) ([]models.MatchTransition, error) {
+	var transitions []models.MatchTransition
+	err := r.db.
+		Where("match_id = ?", matchID).
+		Order("created_at ASC, id ASC").
+		Find(&transitions).Error
+	return transitions, err
+}
+
+// FindResultTimes returns when each match first got its final result, i.e.
+// was finished or awarded, according to its status history. Matches without
+// one in their history are left out.
+func (r *matchRepository) FindResultTimes(matchIDs []uint) (map[uint]time.Time, error) {
+	times := make(map[uint]time.Time, len(matchIDs))
+	if len(matchIDs) == 0 {
+		return times, nil
+	}
+
+	var transitions []models.MatchTransition
+	err := r.db.
+		Select("match_id", "created_at").
+		Where("match_id IN ? AND to_status IN ?", matchIDs, []models.MatchStatus{models.Finished, models.Awarded}).
+		Order("created_at ASC, id ASC").
+		Find(&transitions).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range transitions {
+		if _, ok := times[t.MatchID]; !ok {
+			times[t.MatchID] = t.CreatedAt
+		}
+	}
+	return times, nil
+}
+
 func (r *matchRepository) WithTransaction(txFunc func(repo MatchRepository) error) error {
 	tx := r.db.Begin()
 	if tx.Error != nil {
@@ -5,11 +5,22 @@ import (
 	"xyz-football/internal/models"
 )
 
+// PlayerListFilter narrows the player list down. Name matches any part of
+// the name, ignoring case.
+type PlayerListFilter struct {
+	TeamID   *uint
+	Position models.PlayerPosition
+	Name     string
+}
+
 type PlayerRepository interface {
 	Create(player *models.Player) error
 	FindAll() ([]models.Player, error)
+	List(filter PlayerListFilter, opts ListOptions) ([]models.Player, int64, error)
+	Search(terms []string, number *int, limit int) ([]models.Player, error)
 	FindByID(id uint) (*models.Player, error)
 	FindByTeam(teamID uint) ([]models.Player, error)
+	FindByIDs(ids []uint) ([]models.Player, error)
 	Update(player *models.Player) error
 	Delete(id uint) error
 }
@@ -23,7 +34,14 @@ func NewPlayerRepository(db *gorm.DB) PlayerRepository {
 }
 
 func (r *playerRepository) Create(player *models.Player) error {
-	if err := r.db.Create(player).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(player).Error; err != nil {
+			return err
+		}
+		// Every player starts with an open-ended contract at their team
+		return tx.Create(&models.PlayerContract{PlayerID: player.ID, TeamID: player.TeamID}).Error
+	})
+	if err != nil {
 		return err
 	}
 	// Fetch the created player with team data
@@ -36,6 +54,51 @@ func (r *playerRepository) FindAll() ([]models.Player, error) {
 	return players, err
 }
 
+var playerSortable = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"number":     "number",
+	"position":   "position",
+	"team_id":    "team_id",
+	"height_cm":  "height_cm",
+	"weight_kg":  "weight_kg",
+	"created_at": "created_at",
+}
+
+func (r *playerRepository) List(filter PlayerListFilter, opts ListOptions) ([]models.Player, int64, error) {
+	query := r.db.Model(&models.Player{})
+	if filter.TeamID != nil {
+		query = query.Where("team_id = ?", *filter.TeamID)
+	}
+	if filter.Position != "" {
+		query = query.Where("position = ?", filter.Position)
+	}
+	if filter.Name != "" {
+		query = query.Where("LOWER(name) LIKE ? ESCAPE '!'", containsPattern(filter.Name))
+	}
+
+	var players []models.Player
+	total, err := paginate(query, opts, playerSortable, "name", &players, "Team")
+	return players, total, err
+}
+
+// Search returns players whose name contains every folded term, or who wear
+// the given shirt number.
+func (r *playerRepository) Search(terms []string, number *int, limit int) ([]models.Player, error) {
+	query := searchTerms(r.db.Session(&gorm.Session{NewDB: true}), "search_text", terms)
+	if number != nil {
+		query = r.db.Where(query).Or("number = ?", *number)
+	}
+
+	var players []models.Player
+	err := query.
+		Preload("Team").
+		Order("name ASC, id ASC").
+		Limit(limit).
+		Find(&players).Error
+	return players, err
+}
+
 func (r *playerRepository) FindByID(id uint) (*models.Player, error) {
 	var player models.Player
 	err := r.db.Preload("Team").First(&player, id).Error
@@ -51,6 +114,15 @@ func (r *playerRepository) FindByTeam(teamID uint) ([]models.Player, error) {
 	return players, err
 }
 
+func (r *playerRepository) FindByIDs(ids []uint) ([]models.Player, error) {
+	var players []models.Player
+	if len(ids) == 0 {
+		return players, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&players).Error
+	return players, err
+}
+
 func (r *playerRepository) Update(player *models.Player) error {
 	if err := r.db.Save(player).Error; err != nil {
 		return err
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"xyz-football/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type SeasonRepository interface {
+	Create(season *models.Season) error
+	FindAll() ([]models.Season, error)
+	FindByID(id uint) (*models.Season, error)
+	Update(season *models.Season) error
+	Delete(id uint) error
+}
+
+type seasonRepository struct {
+	db *gorm.DB
+}
+
+func NewSeasonRepository(db *gorm.DB) SeasonRepository {
+	return &seasonRepository{db: db}
+}
+
+func (r *seasonRepository) Create(season *models.Season) error {
+	return r.db.Create(season).Error
+}
+
+func (r *seasonRepository) FindAll() ([]models.Season, error) {
+	var seasons []models.Season
+	err := r.db.Order("start_date DESC").Find(&seasons).Error
+	return seasons, err
+}
+
+func (r *seasonRepository) FindByID(id uint) (*models.Season, error) {
+	var season models.Season
+	err := r.db.First(&season, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+func (r *seasonRepository) Update(season *models.Season) error {
+	// check is exist
+	if _, err := r.FindByID(season.ID); err != nil {
+		return err
+	}
+	return r.db.Save(season).Error
+}
+
+func (r *seasonRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Season{}, id).Error
+}
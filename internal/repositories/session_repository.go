@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"time"
+
+	"xyz-football/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	Create(session *models.Session, token *models.RefreshToken) error
+	FindByID(id uint) (*models.Session, error)
+	FindRefreshToken(hash string) (*models.RefreshToken, error)
+	Rotate(used *models.RefreshToken, next *models.RefreshToken) error
+	Revoke(id uint, at time.Time) error
+	RevokeAll(adminID uint, at time.Time) (int64, error)
+	Prune(now time.Time) error
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// Create starts a session with its first refresh token.
+func (r *sessionRepository) Create(session *models.Session, token *models.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		token.SessionID = session.ID
+		return tx.Create(token).Error
+	})
+}
+
+func (r *sessionRepository) FindByID(id uint) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) FindRefreshToken(hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.Preload("Session").Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate uses up a refresh token and stores the one replacing it, extending
+// the session. A token can only be used once: when it has been used in the
+// meantime nothing changes and gorm.ErrRecordNotFound is returned.
+func (r *sessionRepository) Rotate(used *models.RefreshToken, next *models.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", used.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		used.UsedAt = &now
+
+		next.SessionID = used.SessionID
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Session{}).
+			Where("id = ?", used.SessionID).
+			Update("expires_at", next.ExpiresAt).Error
+	})
+}
+
+func (r *sessionRepository) Revoke(id uint, at time.Time) error {
+	return r.db.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+// RevokeAll logs a user out everywhere and returns the number of sessions
+// that were still open.
+func (r *sessionRepository) RevokeAll(adminID uint, at time.Time) (int64, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("admin_id = ? AND revoked_at IS NULL AND expires_at > ?", adminID, at).
+		Update("revoked_at", at)
+	return result.RowsAffected, result.Error
+}
+
+// Prune deletes the sessions that can no longer be used, with their refresh
+// tokens.
+func (r *sessionRepository) Prune(now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		ended := tx.Model(&models.Session{}).
+			Select("id").
+			Where("expires_at <= ? OR revoked_at IS NOT NULL", now)
+		if err := tx.Where("session_id IN (?)", ended).Delete(&models.RefreshToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("expires_at <= ? OR revoked_at IS NOT NULL", now).Delete(&models.Session{}).Error
+	})
+}
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"xyz-football/internal/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newSessionDB opens an empty in-memory database with a user and one session
+// holding a single refresh token.
+func newSessionDB(t *testing.T) (*gorm.DB, *models.Session, *models.RefreshToken) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // every connection would get a database of its own
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.Admin{}, &models.Session{}, &models.RefreshToken{}); err != nil {
+		t.Fatal(err)
+	}
+	admin := &models.Admin{Name: "Admin", Email: "admin@example.com", Password: "hash", Role: models.RoleSuperAdmin}
+	if err := db.Create(admin).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	session := &models.Session{AdminID: admin.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	token := &models.RefreshToken{TokenHash: "first", ExpiresAt: session.ExpiresAt}
+	if err := NewSessionRepository(db).Create(session, token); err != nil {
+		t.Fatal(err)
+	}
+	return db, session, token
+}
+
+func TestSessionRepositoryRotate(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, db *gorm.DB, used *models.RefreshToken)
+		wantErr error
+	}{
+		{
+			name:    "unused token",
+			prepare: func(t *testing.T, db *gorm.DB, used *models.RefreshToken) {},
+		},
+		{
+			name: "token used before",
+			prepare: func(t *testing.T, db *gorm.DB, used *models.RefreshToken) {
+				if err := db.Model(used).Update("used_at", time.Now()).Error; err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name: "token rotated in the meantime",
+			prepare: func(t *testing.T, db *gorm.DB, used *models.RefreshToken) {
+				stale := *used
+				racer := &models.RefreshToken{TokenHash: "racer", ExpiresAt: time.Now().Add(time.Hour)}
+				if err := NewSessionRepository(db).Rotate(&stale, racer); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, session, used := newSessionDB(t)
+			repo := NewSessionRepository(db)
+			tt.prepare(t, db, used)
+
+			expires := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+			next := &models.RefreshToken{TokenHash: "next", ExpiresAt: expires}
+			err := repo.Rotate(used, next)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Rotate() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var stored int64
+			db.Model(&models.RefreshToken{}).Where("token_hash = ?", "next").Count(&stored)
+			reloaded, err := repo.FindByID(session.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantErr != nil {
+				if stored != 0 {
+					t.Error("a refused rotation stored the next token")
+				}
+				if reloaded.ExpiresAt.Equal(expires) {
+					t.Error("a refused rotation extended the session")
+				}
+				return
+			}
+
+			if used.UsedAt == nil {
+				t.Error("the used token was not marked as used")
+			}
+			if stored != 1 || next.SessionID != session.ID {
+				t.Errorf("next token stored %d times for session %d, want once for session %d", stored, next.SessionID, session.ID)
+			}
+			if !reloaded.ExpiresAt.Equal(expires) {
+				t.Errorf("session expires at %v, want %v", reloaded.ExpiresAt, expires)
+			}
+		})
+	}
+}
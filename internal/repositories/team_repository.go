@@ -1,14 +1,25 @@
 package repositories
 
 import (
+	"strings"
+
 	"xyz-football/internal/models"
 
 	"gorm.io/gorm"
 )
 
+// TeamListFilter narrows the team list down. Name matches any part of the
+// name, ignoring case.
+type TeamListFilter struct {
+	Name string
+	City string
+}
+
 type TeamRepository interface {
 	Create(team *models.Team) error
 	FindAll() ([]models.Team, error)
+	List(filter TeamListFilter, opts ListOptions) ([]models.Team, int64, error)
+	Search(terms []string, limit int) ([]models.Team, error)
 	FindByID(id uint) (*models.Team, error)
 	Update(team *models.Team) error
 	Delete(id uint) error
@@ -32,6 +43,38 @@ func (r *teamRepository) FindAll() ([]models.Team, error) {
 	return teams, err
 }
 
+var teamSortable = map[string]string{
+	"id":           "id",
+	"name":         "name",
+	"city":         "city",
+	"founded_year": "founded_year",
+	"created_at":   "created_at",
+}
+
+func (r *teamRepository) List(filter TeamListFilter, opts ListOptions) ([]models.Team, int64, error) {
+	query := r.db.Model(&models.Team{})
+	if filter.Name != "" {
+		query = query.Where("LOWER(name) LIKE ? ESCAPE '!'", containsPattern(filter.Name))
+	}
+	if filter.City != "" {
+		query = query.Where("LOWER(city) = ?", strings.ToLower(filter.City))
+	}
+
+	var teams []models.Team
+	total, err := paginate(query, opts, teamSortable, "name", &teams)
+	return teams, total, err
+}
+
+// Search returns teams whose name, city or stadium contain every folded term.
+func (r *teamRepository) Search(terms []string, limit int) ([]models.Team, error) {
+	var teams []models.Team
+	err := searchTerms(r.db, "search_text", terms).
+		Order("name ASC, id ASC").
+		Limit(limit).
+		Find(&teams).Error
+	return teams, err
+}
+
 func (r *teamRepository) FindByID(id uint) (*models.Team, error) {
 	var team models.Team
 	err := r.db.First(&team, id).Error
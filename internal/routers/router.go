@@ -1,63 +1,141 @@
 package routers
 
 import (
+	"log"
+	"time"
+
+	"xyz-football/config"
 	"xyz-football/internal/handlers"
 	"xyz-football/internal/middleware"
+	"xyz-football/internal/models"
 	"xyz-football/internal/repositories"
 	"xyz-football/internal/services"
+	"xyz-football/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func Setup(db *gorm.DB) *gin.Engine {
+// The public API is open to anyone, so it is limited per client and may be
+// cached by browsers and proxies for a short while.
+const (
+	publicRateLimit  = 60
+	publicRateWindow = time.Minute
+	publicCacheAge   = time.Minute
+	publicMaxLimit   = 50 // rows in a public leaderboard
+)
+
+func Setup(db *gorm.DB, cfg *config.Config) *gin.Engine {
 	r := gin.Default()
+	// Without trusted proxies ClientIP is the address of the connection, so
+	// a client cannot pick its own through X-Forwarded-For
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	tokens, err := utils.NewJWTManager(cfg)
+	if err != nil {
+		log.Fatalf("failed to set up JWT signing: %v", err)
+	}
 
 	// Initialize repositories
 	repo := struct {
-		team   repositories.TeamRepository
-		player repositories.PlayerRepository
-		match  repositories.MatchRepository
-		goal   repositories.GoalRepository
-		admin  repositories.AdminRepository
+		team        repositories.TeamRepository
+		player      repositories.PlayerRepository
+		match       repositories.MatchRepository
+		goal        repositories.GoalRepository
+		card        repositories.CardRepository
+		lineup      repositories.LineupRepository
+		contract    repositories.ContractRepository
+		admin       repositories.AdminRepository
+		season      repositories.SeasonRepository
+		competition repositories.CompetitionRepository
+		bracket     repositories.BracketRepository
+		imports     repositories.ImportRepository
+		session     repositories.SessionRepository
 	}{
-		team:   repositories.NewTeamRepository(db),
-		player: repositories.NewPlayerRepository(db),
-		match:  repositories.NewMatchRepository(db),
-		goal:   repositories.NewGoalRepository(db),
-		admin:  repositories.NewAdminRepository(db),
+		team:        repositories.NewTeamRepository(db),
+		player:      repositories.NewPlayerRepository(db),
+		match:       repositories.NewMatchRepository(db),
+		goal:        repositories.NewGoalRepository(db),
+		card:        repositories.NewCardRepository(db),
+		lineup:      repositories.NewLineupRepository(db),
+		contract:    repositories.NewContractRepository(db),
+		admin:       repositories.NewAdminRepository(db),
+		season:      repositories.NewSeasonRepository(db),
+		competition: repositories.NewCompetitionRepository(db),
+		bracket:     repositories.NewBracketRepository(db),
+		imports:     repositories.NewImportRepository(db),
+		session:     repositories.NewSessionRepository(db),
 	}
 
 	// Initialize services
 	svc := struct {
-		team   services.TeamService
-		player services.PlayerService
-		match  services.MatchService
-		report services.ReportService
-		admin  services.AdminService
+		team        services.TeamService
+		player      services.PlayerService
+		match       services.MatchService
+		report      services.ReportService
+		admin       services.AdminService
+		season      services.SeasonService
+		competition services.CompetitionService
+		bracket     services.BracketService
+		discipline  services.DisciplineService
+		lineup      services.LineupService
+		stats       services.StatsService
+		search      services.SearchService
+		imports     services.ImportService
 	}{
-		team:   services.NewTeamService(repo.team),
-		player: services.NewPlayerService(repo.player),
-		match:  services.NewMatchService(repo.match, repo.goal),
-		report: services.NewReportService(repo.match, repo.team),
-		admin:  services.NewAdminService(repo.admin),
+		team:        services.NewTeamService(repo.team),
+		player:      services.NewPlayerService(repo.player, repo.contract, repo.team),
+		match:       services.NewMatchService(repo.match, repo.goal, repo.player, repo.card, repo.lineup, repo.contract, repo.team, repo.season, repo.competition),
+		report:      services.NewReportService(repo.match, repo.lineup, repo.team, repo.competition),
+		admin:       services.NewAdminService(repo.admin, repo.session, tokens, cfg.JWTRefreshTTL),
+		season:      services.NewSeasonService(repo.season),
+		competition: services.NewCompetitionService(repo.competition),
+		bracket:     services.NewBracketService(repo.bracket, repo.match, repo.team, repo.season, repo.competition),
+		discipline:  services.NewDisciplineService(repo.card, repo.match, repo.player),
+		lineup:      services.NewLineupService(repo.lineup, repo.match, repo.contract, repo.card),
+		stats:       services.NewStatsService(repo.match, repo.goal, repo.lineup, repo.player, repo.contract, repo.team),
+		search:      services.NewSearchService(repo.team, repo.player),
+		imports:     services.NewImportService(repo.imports, repo.team, repo.player, repo.season, repo.competition),
 	}
 
 	// Initialize handlers
 	h := struct {
-		team   *handlers.TeamHandler
-		player *handlers.PlayerHandler
-		match  *handlers.MatchHandler
-		report *handlers.ReportHandler
-		admin  *handlers.AdminHandler
+		team        *handlers.TeamHandler
+		player      *handlers.PlayerHandler
+		match       *handlers.MatchHandler
+		report      *handlers.ReportHandler
+		admin       *handlers.AdminHandler
+		season      *handlers.SeasonHandler
+		competition *handlers.CompetitionHandler
+		bracket     *handlers.BracketHandler
+		discipline  *handlers.DisciplineHandler
+		lineup      *handlers.LineupHandler
+		stats       *handlers.StatsHandler
+		search      *handlers.SearchHandler
+		imports     *handlers.ImportHandler
+		public      *handlers.PublicHandler
 	}{
-		team:   handlers.NewTeamHandler(svc.team),
-		player: handlers.NewPlayerHandler(svc.player),
-		match:  handlers.NewMatchHandler(svc.match),
-		report: handlers.NewReportHandler(svc.report),
-		admin:  handlers.NewAdminHandler(svc.admin),
+		team:        handlers.NewTeamHandler(svc.team),
+		player:      handlers.NewPlayerHandler(svc.player),
+		match:       handlers.NewMatchHandler(svc.match),
+		report:      handlers.NewReportHandler(svc.report),
+		admin:       handlers.NewAdminHandler(svc.admin),
+		season:      handlers.NewSeasonHandler(svc.season),
+		competition: handlers.NewCompetitionHandler(svc.competition),
+		bracket:     handlers.NewBracketHandler(svc.bracket),
+		discipline:  handlers.NewDisciplineHandler(svc.discipline),
+		lineup:      handlers.NewLineupHandler(svc.lineup),
+		stats:       handlers.NewStatsHandler(svc.stats),
+		search:      handlers.NewSearchHandler(svc.search),
+		imports:     handlers.NewImportHandler(svc.imports),
+		public:      handlers.NewPublicHandler(svc.team, svc.player, svc.match, svc.report),
 	}
 
+	// Public keys for verifying tokens, when they are signed asymmetrically
+	r.GET("/.well-known/jwks.json", h.admin.JWKS)
+
 	// Public routes (no authentication required)
 	public := r.Group("/api/v1")
 	{
@@ -65,27 +143,65 @@ func Setup(db *gorm.DB) *gin.Engine {
 		auth := public.Group("/admin")
 		{
 			auth.POST("/login", h.admin.Login)
-			auth.POST("/register", h.admin.Register)
+			auth.POST("/refresh", h.admin.Refresh)
+			auth.POST("/setup", h.admin.Setup)
+			auth.POST("/invitations/accept", h.admin.AcceptInvitation)
 		}
 
+		// Read-only API for fans and the website; everything that changes
+		// data stays behind authentication below
+		open := public.Group("/public")
+		open.Use(
+			middleware.RateLimit(publicRateLimit, publicRateWindow),
+			middleware.PublicQuery(publicMaxLimit),
+			middleware.Cache(publicCacheAge),
+		)
+		{
+			open.GET("/teams", h.public.ListTeams)
+			open.GET("/teams/:id", h.public.GetTeam)
+			open.GET("/players", h.public.ListPlayers)
+			open.GET("/players/:id", h.public.GetPlayer)
+			open.GET("/matches", h.public.ListMatches)
+			open.GET("/matches/:id", h.public.GetMatch)
+
+			openReports := open.Group("/reports")
+			{
+				openReports.GET("/standings", h.report.GetStandings)
+				openReports.GET("/standings/history", h.report.GetStandingsHistory)
+				openReports.GET("/top-scorers", h.report.GetTopScorers)
+				openReports.GET("/top-assists", h.report.GetTopAssists)
+				openReports.GET("/goal-contributions", h.report.GetGoalContributions)
+				openReports.GET("/head-to-head", h.report.GetHeadToHead)
+				openReports.GET("/matches/:id", h.report.GetMatchReport)
+			}
+		}
 	}
 
-	// Protected routes (require authentication)
+	// Protected routes (require authentication), each group checks the
+	// permissions of the caller's role
 	api := r.Group("/api/v1")
-	api.Use(middleware.JWTAuthMiddleware())
+	api.Use(middleware.JWTAuthMiddleware(svc.admin))
 	{
+		api.POST("/admin/logout", h.admin.Logout)
+		api.POST("/admin/logout-all", h.admin.LogoutAll)
+
+		api.GET("/search", middleware.Require(models.PermRead), h.search.Search)
+
 		// Team management
 		teams := api.Group("/teams")
+		teams.Use(middleware.Authorize(models.PermRead, models.PermManageLeague))
 		{
 			teams.GET("", h.team.List)
 			teams.GET("/:id", h.team.Get)
 			teams.POST("", h.team.Create)
 			teams.PUT("/:id", h.team.Update)
 			teams.DELETE("/:id", h.team.Delete)
+			teams.GET("/:id/stats", h.stats.GetTeamStats)
 		}
 
 		// Player management
 		players := api.Group("/players")
+		players.Use(middleware.Authorize(models.PermRead, models.PermManageLeague))
 		{
 			players.GET("", h.player.List)
 			players.GET("/:id", h.player.Get)
@@ -93,32 +209,104 @@ func Setup(db *gorm.DB) *gin.Engine {
 			players.PUT("/:id", h.player.Update)
 			players.DELETE("/:id", h.player.Delete)
 			players.GET("/by-team/:teamId", h.player.ListByTeam)
+			players.GET("/:id/discipline", h.discipline.GetPlayerDiscipline)
+			players.GET("/:id/stats", h.stats.GetPlayerStats)
+			players.GET("/:id/contracts", h.player.Contracts)
+			players.POST("/:id/transfer", h.player.Transfer)
 		}
 
-		// Match management
+		// Match management; officials run matches, the fixtures themselves
+		// belong to the league
+		manageLeague := middleware.Require(models.PermManageLeague)
 		matches := api.Group("/matches")
+		matches.Use(middleware.Authorize(models.PermRead, models.PermManageMatches))
 		{
 			matches.GET("", h.match.List)
 			matches.GET("/:id", h.match.Get)
 			matches.GET("/by-team/:teamId", h.match.GetByTeam)
-			matches.POST("", h.match.Create)
-			matches.PUT("/:id", h.match.Update)
-			matches.DELETE("/:id", h.match.Delete)
+			matches.POST("", manageLeague, h.match.Create)
+			matches.PUT("/:id", manageLeague, h.match.Update)
+			matches.DELETE("/:id", manageLeague, h.match.Delete)
 			matches.POST("/:id/report", h.match.ReportResult)
+			matches.GET("/:id/history", h.match.History)
+
+			// Lifecycle transitions
+			matches.POST("/:id/kickoff", h.match.Kickoff)
+			matches.POST("/:id/half-time", h.match.HalfTime)
+			matches.POST("/:id/resume", h.match.Resume)
+			matches.POST("/:id/postpone", h.match.Postpone)
+			matches.POST("/:id/reschedule", h.match.Reschedule)
+			matches.POST("/:id/cancel", h.match.Cancel)
+			matches.POST("/:id/abandon", h.match.Abandon)
+			matches.POST("/:id/award", h.match.Award)
+
+			// Lineups and substitutions
+			matches.GET("/:id/lineups", h.lineup.Get)
+			matches.POST("/:id/lineups", h.lineup.Submit)
+			matches.POST("/:id/substitutions", h.lineup.AddSubstitution)
+			matches.DELETE("/:id/substitutions/:subId", h.lineup.DeleteSubstitution)
+		}
+
+		// Season management
+		seasons := api.Group("/seasons")
+		seasons.Use(middleware.Authorize(models.PermRead, models.PermManageLeague))
+		{
+			seasons.GET("", h.season.List)
+			seasons.GET("/:id", h.season.Get)
+			seasons.POST("", h.season.Create)
+			seasons.PUT("/:id", h.season.Update)
+			seasons.DELETE("/:id", h.season.Delete)
+			seasons.POST("/:id/fixtures/generate", h.match.GenerateFixtures)
+		}
+
+		// Competition management
+		competitions := api.Group("/competitions")
+		competitions.Use(middleware.Authorize(models.PermRead, models.PermManageLeague))
+		{
+			competitions.GET("", h.competition.List)
+			competitions.GET("/:id", h.competition.Get)
+			competitions.POST("", h.competition.Create)
+			competitions.PUT("/:id", h.competition.Update)
+			competitions.DELETE("/:id", h.competition.Delete)
+			competitions.GET("/:id/bracket", h.bracket.Get)
+			competitions.POST("/:id/bracket", h.bracket.Create)
+		}
+
+		// Bulk import, from a JSON array or CSV
+		imports := api.Group("/import")
+		imports.Use(middleware.Require(models.PermManageLeague))
+		{
+			imports.POST("/teams", h.imports.Teams)
+			imports.POST("/players", h.imports.Players)
+			imports.POST("/matches", h.imports.Matches)
 		}
 
 		reports := api.Group("/reports")
+		reports.Use(middleware.Require(models.PermRead))
 		{
 			reports.GET("/standings", h.report.GetStandings)
+			reports.GET("/standings/history", h.report.GetStandingsHistory)
 			reports.GET("/top-scorers", h.report.GetTopScorers)
+			reports.GET("/top-assists", h.report.GetTopAssists)
+			reports.GET("/goal-contributions", h.report.GetGoalContributions)
+			reports.GET("/discipline", h.discipline.GetReport)
+			reports.GET("/head-to-head", h.report.GetHeadToHead)
+			reports.GET("/matches", h.report.GetMatchReports)
 			reports.GET("/matches/:id", h.report.GetMatchReport)
 		}
 
-		// Admin management
-		// admin := api.Group("/admin")
-		// {
-		// 	// Add admin-only endpoints here if needed
-		// }
+		// User management
+		users := api.Group("/users")
+		users.Use(middleware.Require(models.PermManageUsers))
+		{
+			users.GET("", h.admin.ListUsers)
+			users.PUT("/:id/role", h.admin.ChangeRole)
+			users.DELETE("/:id", h.admin.DeleteUser)
+			users.DELETE("/:id/sessions", h.admin.EndSessions)
+			users.GET("/invitations", h.admin.ListInvitations)
+			users.POST("/invitations", h.admin.Invite)
+			users.DELETE("/invitations/:id", h.admin.RevokeInvitation)
+		}
 	}
 
 	return r
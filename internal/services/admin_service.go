@@ -1,65 +1,388 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"strings"
+	"time"
+
 	"xyz-football/internal/models"
 	"xyz-football/internal/repositories"
 	"xyz-football/pkg/utils"
 
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
+// invitationTTL is how long an invitation can be accepted.
+const invitationTTL = 7 * 24 * time.Hour
+
+// ErrSetupDone means the first user exists and everyone else has to be invited.
+var ErrSetupDone = errors.New("setup is already done, ask an admin for an invitation")
+
+var (
+	errInvalidRefreshToken = errors.New("refresh token is invalid or has expired")
+	errSessionEnded        = errors.New("session has ended, please log in again")
+)
+
+// TokenPair is what a login or refresh hands out: a short-lived access token
+// and the refresh token to get the next one with.
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int // seconds until the access token expires
+}
+
 type AdminService interface {
-	Login(email, password string) (string, *models.Admin, error)
-	Register(admin *models.Admin) error
+	Login(email, password string) (*TokenPair, *models.Admin, error)
+	Refresh(refreshToken string) (*TokenPair, error)
+	Logout(sessionID uint) error
+	LogoutAll(adminID uint) (int64, error)
+	VerifyAccessToken(token string) (*utils.JWTClaim, error)
+	Setup(admin *models.Admin) error
 	FindByEmail(email string) (*models.Admin, error)
+
+	ListUsers() ([]models.Admin, error)
+	ChangeRole(id uint, role models.Role) (*models.Admin, error)
+	DeleteUser(actorID, id uint) error
+
+	Invite(actorID uint, email string, role models.Role) (*models.Invitation, string, error)
+	ListInvitations() ([]models.Invitation, error)
+	RevokeInvitation(id uint) error
+	AcceptInvitation(token, name, password string) (*models.Admin, error)
+
+	JWKS() (utils.JWKS, bool)
 }
 
 type adminService struct {
-	repo repositories.AdminRepository
+	repo       repositories.AdminRepository
+	sessions   repositories.SessionRepository
+	tokens     *utils.JWTManager
+	refreshTTL time.Duration
 }
 
-func NewAdminService(repo repositories.AdminRepository) AdminService {
-	return &adminService{repo: repo}
+func NewAdminService(
+	repo repositories.AdminRepository,
+	sessions repositories.SessionRepository,
+	tokens *utils.JWTManager,
+	refreshTTL time.Duration,
+) AdminService {
+	return &adminService{repo: repo, sessions: sessions, tokens: tokens, refreshTTL: refreshTTL}
 }
 
-func (s *adminService) Login(email, password string) (string, *models.Admin, error) {
-	admin, err := s.repo.FindByEmail(email)
+// Login starts a new session for the user.
+func (s *adminService) Login(email, password string) (*TokenPair, *models.Admin, error) {
+	admin, err := s.repo.FindByEmail(normalizeEmail(email))
 	if err != nil {
-		return "", nil, errors.New("invalid email or password")
+		return nil, nil, errors.New("invalid email or password")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(password)); err != nil {
-		return "", nil, errors.New("invalid email or password")
+		return nil, nil, errors.New("invalid email or password")
+	}
+
+	now := time.Now()
+	// Sessions that have ended are only kept until the next login
+	if err := s.sessions.Prune(now); err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, err := randomToken()
+	if err != nil {
+		return nil, nil, errors.New("failed to generate token")
+	}
+	session := &models.Session{AdminID: admin.ID, ExpiresAt: now.Add(s.refreshTTL)}
+	refresh := &models.RefreshToken{TokenHash: hashToken(refreshToken), ExpiresAt: session.ExpiresAt}
+	if err := s.sessions.Create(session, refresh); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.tokenPair(admin, session.ID, refreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tokens, admin, nil
+}
+
+// Refresh trades a refresh token for a new access token and the next refresh
+// token. Refresh tokens are single-use: one that comes back after it was used
+// has been copied, so its whole session is ended.
+func (s *adminService) Refresh(refreshToken string) (*TokenPair, error) {
+	now := time.Now()
+	token, err := s.sessions.FindRefreshToken(hashToken(refreshToken))
+	if err != nil || !token.Session.Active(now) || !now.Before(token.ExpiresAt) {
+		return nil, errInvalidRefreshToken
+	}
+	if token.UsedAt != nil {
+		_ = s.sessions.Revoke(token.SessionID, now)
+		return nil, errInvalidRefreshToken
+	}
+
+	admin, err := s.repo.FindByID(token.Session.AdminID)
+	if err != nil {
+		_ = s.sessions.Revoke(token.SessionID, now)
+		return nil, errInvalidRefreshToken
+	}
+
+	nextToken, err := randomToken()
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+	next := &models.RefreshToken{TokenHash: hashToken(nextToken), ExpiresAt: now.Add(s.refreshTTL)}
+	if err := s.sessions.Rotate(token, next); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Used by a concurrent refresh in the meantime
+			_ = s.sessions.Revoke(token.SessionID, now)
+			return nil, errInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	return s.tokenPair(admin, token.SessionID, nextToken)
+}
+
+// Logout ends one session, revoking its access and refresh tokens.
+func (s *adminService) Logout(sessionID uint) error {
+	return s.sessions.Revoke(sessionID, time.Now())
+}
+
+// LogoutAll ends every session of the user and returns how many there were.
+func (s *adminService) LogoutAll(adminID uint) (int64, error) {
+	return s.sessions.RevokeAll(adminID, time.Now())
+}
+
+// VerifyAccessToken checks the signature and claims of an access token, then
+// that its session has not ended.
+func (s *adminService) VerifyAccessToken(token string) (*utils.JWTClaim, error) {
+	claims, err := s.tokens.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessions.FindByID(claims.SessionID)
+	if err != nil || session.AdminID != claims.UserID || !session.Active(time.Now()) {
+		return nil, errSessionEnded
 	}
+	return claims, nil
+}
 
-	// Generate JWT token
-	token, err := generateJWT(admin.ID)
+func (s *adminService) tokenPair(admin *models.Admin, sessionID uint, refreshToken string) (*TokenPair, error) {
+	accessToken, err := s.tokens.GenerateToken(admin.ID, string(admin.Role), sessionID)
 	if err != nil {
-		return "", nil, errors.New("failed to generate token")
+		return nil, errors.New("failed to generate token")
+	}
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.tokens.TTL().Seconds()),
+	}, nil
+}
+
+// Setup registers the first user, as super-admin. Everyone after that joins
+// through an invitation.
+func (s *adminService) Setup(admin *models.Admin) error {
+	admin.Email = normalizeEmail(admin.Email)
+	if err := hashPassword(admin); err != nil {
+		return err
 	}
+	admin.Role = models.RoleSuperAdmin
+
+	if err := s.repo.CreateFirst(admin); err != nil {
+		if errors.Is(err, repositories.ErrSetupDone) {
+			return ErrSetupDone
+		}
+		return err
+	}
+	return nil
+}
 
-	admin.Password = "" // remove password from response
+func (s *adminService) FindByEmail(email string) (*models.Admin, error) {
+	return s.repo.FindByEmail(normalizeEmail(email))
+}
 
-	return token, admin, nil
+func (s *adminService) ListUsers() ([]models.Admin, error) {
+	return s.repo.FindAll()
 }
 
-func (s *adminService) Register(admin *models.Admin) error {
-	// Hash password before saving
+// ChangeRole gives a user another role and ends their sessions, so they log
+// in again with it. The last super-admin keeps theirs, so there is always
+// someone left to manage users.
+func (s *adminService) ChangeRole(id uint, role models.Role) (*models.Admin, error) {
+	if !role.Valid() {
+		return nil, errors.New("invalid role")
+	}
+	admin, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if admin.Role == models.RoleSuperAdmin && role != models.RoleSuperAdmin {
+		if err := s.keepSuperAdmin(); err != nil {
+			return nil, err
+		}
+	}
+
+	changed := admin.Role != role
+	admin.Role = role
+	if err := s.repo.Update(admin); err != nil {
+		return nil, err
+	}
+	// Access tokens carry the role, so the old one would hold until they
+	// expire; logging the user out makes the change take effect right away
+	if changed {
+		if _, err := s.sessions.RevokeAll(admin.ID, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	return admin, nil
+}
+
+func (s *adminService) DeleteUser(actorID, id uint) error {
+	if actorID == id {
+		return errors.New("you cannot delete your own account")
+	}
+	admin, err := s.repo.FindByID(id)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if admin.Role == models.RoleSuperAdmin {
+		if err := s.keepSuperAdmin(); err != nil {
+			return err
+		}
+	}
+	if _, err := s.sessions.RevokeAll(id, time.Now()); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *adminService) keepSuperAdmin() error {
+	count, err := s.repo.CountByRole(models.RoleSuperAdmin)
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return errors.New("the last super-admin cannot be removed or demoted")
+	}
+	return nil
+}
+
+// Invite creates an invitation for the email and returns it with its token.
+// The token is not stored and cannot be shown again.
+func (s *adminService) Invite(actorID uint, email string, role models.Role) (*models.Invitation, string, error) {
+	if !role.Valid() {
+		return nil, "", errors.New("invalid role")
+	}
+	email = normalizeEmail(email)
+
+	if _, err := s.repo.FindByEmail(email); err == nil {
+		return nil, "", errors.New("a user with this email already exists")
+	}
+	pending, err := s.repo.FindPendingInvitations(time.Now())
+	if err != nil {
+		return nil, "", err
+	}
+	for _, invitation := range pending {
+		if invitation.Email == email {
+			return nil, "", errors.New("an invitation for this email is still pending")
+		}
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, "", errors.New("failed to generate invitation token")
+	}
+
+	invitation := &models.Invitation{
+		Email:       email,
+		Role:        role,
+		TokenHash:   hashToken(token),
+		InvitedByID: actorID,
+		ExpiresAt:   time.Now().Add(invitationTTL),
+	}
+	if err := s.repo.CreateInvitation(invitation); err != nil {
+		return nil, "", err
+	}
+	return invitation, token, nil
+}
+
+func (s *adminService) ListInvitations() ([]models.Invitation, error) {
+	return s.repo.FindPendingInvitations(time.Now())
+}
+
+func (s *adminService) RevokeInvitation(id uint) error {
+	invitation, err := s.repo.FindInvitationByID(id)
+	if err != nil {
+		return errors.New("invitation not found")
+	}
+	if invitation.AcceptedAt != nil {
+		return errors.New("invitation has already been accepted")
+	}
+	return s.repo.DeleteInvitation(id)
+}
+
+// AcceptInvitation signs up the invited user with the role they were invited for.
+func (s *adminService) AcceptInvitation(token, name, password string) (*models.Admin, error) {
+	invitation, err := s.repo.FindInvitationByTokenHash(hashToken(token))
+	if err != nil || !invitation.Pending(time.Now()) {
+		return nil, errors.New("invitation is invalid or has expired")
+	}
+	if _, err := s.repo.FindByEmail(invitation.Email); err == nil {
+		return nil, errors.New("a user with this email already exists")
+	}
+
+	admin := &models.Admin{
+		Name:     name,
+		Email:    invitation.Email,
+		Password: password,
+		Role:     invitation.Role,
+	}
+	if err := hashPassword(admin); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AcceptInvitation(invitation, admin); err != nil {
+		return nil, errors.New("invitation is invalid or has expired")
+	}
+
+	return admin, nil
+}
+
+// JWKS returns the public keys tokens are signed with, if they are asymmetric.
+func (s *adminService) JWKS() (utils.JWKS, bool) {
+	return s.tokens.JWKS()
+}
+
+// normalizeEmail is how emails are stored and looked up, so the same address
+// matches whatever case it is typed in.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func hashPassword(admin *models.Admin) error {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(admin.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return errors.New("failed to hash password")
 	}
-
 	admin.Password = string(hashedPassword)
-	return s.repo.Create(admin)
+	return nil
 }
 
-func (s *adminService) FindByEmail(email string) (*models.Admin, error) {
-	return s.repo.FindByEmail(email)
+// randomToken makes an opaque token for invitations and refresh tokens.
+func randomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
 }
 
-// Helper function to generate JWT token
-func generateJWT(userID uint) (string, error) {
-	return utils.GenerateToken(userID)
+// hashToken is how invitation and refresh tokens are stored and looked up.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
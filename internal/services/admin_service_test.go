@@ -0,0 +1,133 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"xyz-football/config"
+	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
+	"xyz-football/pkg/utils"
+)
+
+// newAdminService runs the admin service on an empty in-memory database with
+// a super-admin who can log in as admin@example.com.
+func newAdminService(t *testing.T) (AdminService, repositories.SessionRepository) {
+	t.Helper()
+
+	db := newTestDB(t)
+	tokens, err := utils.NewJWTManager(&config.Config{
+		JWTAlgorithm: "HS256",
+		JWTSecret:    "test-secret-that-is-long-enough-for-hs256",
+		JWTKeyID:     "test",
+		JWTTTL:       time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := repositories.NewSessionRepository(db)
+	svc := NewAdminService(repositories.NewAdminRepository(db), sessions, tokens, time.Hour)
+	if err := svc.Setup(&models.Admin{Name: "Admin", Email: "admin@example.com", Password: "secret123"}); err != nil {
+		t.Fatal(err)
+	}
+	return svc, sessions
+}
+
+func TestAdminServiceRefresh(t *testing.T) {
+	tests := []struct {
+		name string
+		// prepare gets the pair handed out at login and returns the refresh
+		// token to present
+		prepare       func(t *testing.T, svc AdminService, login *TokenPair) string
+		wantErr       bool
+		wantSessionOn bool
+	}{
+		{
+			name:          "fresh token",
+			prepare:       func(t *testing.T, svc AdminService, login *TokenPair) string { return login.RefreshToken },
+			wantSessionOn: true,
+		},
+		{
+			name: "next token after a refresh",
+			prepare: func(t *testing.T, svc AdminService, login *TokenPair) string {
+				next, err := svc.Refresh(login.RefreshToken)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return next.RefreshToken
+			},
+			wantSessionOn: true,
+		},
+		{
+			name: "reused token ends the session",
+			prepare: func(t *testing.T, svc AdminService, login *TokenPair) string {
+				if _, err := svc.Refresh(login.RefreshToken); err != nil {
+					t.Fatal(err)
+				}
+				return login.RefreshToken
+			},
+			wantErr: true,
+		},
+		{
+			name:    "unknown token",
+			prepare: func(t *testing.T, svc AdminService, login *TokenPair) string { return "not-a-token" },
+			wantErr: true,
+			// an unknown token says nothing about the session
+			wantSessionOn: true,
+		},
+		{
+			name: "token of a logged out session",
+			prepare: func(t *testing.T, svc AdminService, login *TokenPair) string {
+				claims, err := svc.VerifyAccessToken(login.AccessToken)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := svc.Logout(claims.SessionID); err != nil {
+					t.Fatal(err)
+				}
+				return login.RefreshToken
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, sessions := newAdminService(t)
+			login, _, err := svc.Login("Admin@Example.com", "secret123")
+			if err != nil {
+				t.Fatal(err)
+			}
+			claims, err := svc.VerifyAccessToken(login.AccessToken)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pair, err := svc.Refresh(tt.prepare(t, svc, login))
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Refresh() succeeded, want an error")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Refresh() error = %v", err)
+				}
+				if _, err := svc.VerifyAccessToken(pair.AccessToken); err != nil {
+					t.Errorf("refreshed access token is rejected: %v", err)
+				}
+			}
+
+			session, err := sessions.FindByID(claims.SessionID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if on := session.Active(time.Now()); on != tt.wantSessionOn {
+				t.Errorf("session active = %v, want %v", on, tt.wantSessionOn)
+			}
+			if _, err := svc.VerifyAccessToken(login.AccessToken); (err == nil) != tt.wantSessionOn {
+				t.Errorf("access token from the login accepted = %v, want %v", err == nil, tt.wantSessionOn)
+			}
+		})
+	}
+}
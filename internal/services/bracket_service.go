@@ -0,0 +1,519 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type BracketService interface {
+	CreateBracket(competitionID uint, opts BracketOptions) (*Bracket, error)
+	GetBracket(competitionID uint, seasonID *uint) (*Bracket, error)
+}
+
+// BracketOptions describes a knockout draw. TeamIDs are given in seed order,
+// best seed first; when the field is not a power of two the top seeds get byes.
+type BracketOptions struct {
+	SeasonID       *uint
+	TeamIDs        []uint
+	StartDate      time.Time
+	IntervalDays   int // days between legs/rounds, defaults to 7
+	TwoLegged      bool
+	TwoLeggedFinal bool
+	AwayGoalsRule  bool
+}
+
+type bracketService struct {
+	repo            repositories.BracketRepository
+	matchRepo       repositories.MatchRepository
+	teamRepo        repositories.TeamRepository
+	seasonRepo      repositories.SeasonRepository
+	competitionRepo repositories.CompetitionRepository
+}
+
+type Bracket struct {
+	CompetitionID uint           `json:"competition_id"`
+	SeasonID      *uint          `json:"season_id,omitempty"`
+	Rounds        []BracketRound `json:"rounds"`
+	ChampionID    *uint          `json:"champion_id,omitempty"`
+}
+
+type BracketRound struct {
+	Round int          `json:"round"`
+	Name  string       `json:"name"`
+	Ties  []BracketTie `json:"ties"`
+}
+
+type BracketTie struct {
+	TieID         uint         `json:"tie_id"`
+	Position      int          `json:"position"`
+	HomeTeam      *BracketTeam `json:"home_team,omitempty"`
+	AwayTeam      *BracketTeam `json:"away_team,omitempty"`
+	Bye           bool         `json:"bye"`
+	TwoLegged     bool         `json:"two_legged"`
+	FirstLegAt    time.Time    `json:"first_leg_at"`
+	SecondLegAt   *time.Time   `json:"second_leg_at,omitempty"`
+	Legs          []BracketLeg `json:"legs,omitempty"`
+	AggregateHome *int         `json:"aggregate_home,omitempty"`
+	AggregateAway *int         `json:"aggregate_away,omitempty"`
+	WinnerTeamID  *uint        `json:"winner_team_id,omitempty"`
+	NextTieID     *uint        `json:"next_tie_id,omitempty"`
+}
+
+type BracketTeam struct {
+	TeamID uint   `json:"team_id"`
+	Name   string `json:"name"`
+	Seed   int    `json:"seed,omitempty"`
+}
+
+type BracketLeg struct {
+	MatchID            uint   `json:"match_id"`
+	Leg                int    `json:"leg"`
+	MatchTime          string `json:"match_time"`
+	Status             string `json:"status"`
+	HomeTeamID         uint   `json:"home_team_id"`
+	AwayTeamID         uint   `json:"away_team_id"`
+	HomeScore          *int   `json:"home_score,omitempty"`
+	AwayScore          *int   `json:"away_score,omitempty"`
+	HomeExtraTimeScore *int   `json:"home_extra_time_score,omitempty"`
+	AwayExtraTimeScore *int   `json:"away_extra_time_score,omitempty"`
+	HomePenalties      *int   `json:"home_penalties,omitempty"`
+	AwayPenalties      *int   `json:"away_penalties,omitempty"`
+}
+
+func NewBracketService(
+	repo repositories.BracketRepository,
+	matchRepo repositories.MatchRepository,
+	teamRepo repositories.TeamRepository,
+	seasonRepo repositories.SeasonRepository,
+	competitionRepo repositories.CompetitionRepository,
+) BracketService {
+	return &bracketService{
+		repo:            repo,
+		matchRepo:       matchRepo,
+		teamRepo:        teamRepo,
+		seasonRepo:      seasonRepo,
+		competitionRepo: competitionRepo,
+	}
+}
+
+func (s *bracketService) CreateBracket(competitionID uint, opts BracketOptions) (*Bracket, error) {
+	competition, err := s.competitionRepo.FindByID(competitionID)
+	if err != nil {
+		return nil, errors.New("competition not found")
+	}
+	if competition.Type != models.Cup {
+		return nil, errors.New("brackets can only be created for cup competitions")
+	}
+
+	if opts.SeasonID != nil {
+		if _, err := s.seasonRepo.FindByID(*opts.SeasonID); err != nil {
+			return nil, errors.New("season not found")
+		}
+	}
+
+	existing, err := s.repo.FindByCompetition(competitionID, opts.SeasonID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, errors.New("a bracket already exists for this competition and season")
+	}
+
+	if len(opts.TeamIDs) < 2 {
+		return nil, errors.New("at least two teams are required")
+	}
+
+	// Validate teams exist and are not listed twice
+	seen := make(map[uint]bool, len(opts.TeamIDs))
+	for _, id := range opts.TeamIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("team %d is listed more than once", id)
+		}
+		if _, err := s.teamRepo.FindByID(id); err != nil {
+			return nil, fmt.Errorf("team %d not found", id)
+		}
+		seen[id] = true
+	}
+
+	interval := opts.IntervalDays
+	if interval <= 0 {
+		interval = 7
+	}
+
+	size := 2
+	for size < len(opts.TeamIDs) {
+		size *= 2
+	}
+	totalRounds := 0
+	for n := size; n > 1; n /= 2 {
+		totalRounds++
+	}
+	seeds := seedOrder(size)
+
+	err = s.matchRepo.WithTransaction(func(repo repositories.MatchRepository) error {
+		brackets := repositories.NewBracketRepository(repo.GetDB())
+
+		// Lay out every round up front so the tree is complete from day one
+		var opening []*models.CupTie
+		slot := 0
+		for round := 1; round <= totalRounds; round++ {
+			twoLegged := opts.TwoLegged && (round < totalRounds || opts.TwoLeggedFinal)
+			firstLegAt := opts.StartDate.AddDate(0, 0, slot*interval)
+			var secondLegAt *time.Time
+			if twoLegged {
+				t := opts.StartDate.AddDate(0, 0, (slot+1)*interval)
+				secondLegAt = &t
+				slot += 2
+			} else {
+				slot++
+			}
+
+			ties := size >> round
+			for position := 1; position <= ties; position++ {
+				tie := &models.CupTie{
+					CompetitionID: competitionID,
+					SeasonID:      opts.SeasonID,
+					Round:         round,
+					Position:      position,
+					TwoLegged:     twoLegged,
+					AwayGoalsRule: opts.AwayGoalsRule,
+					FirstLegAt:    firstLegAt,
+					SecondLegAt:   secondLegAt,
+				}
+				if round == 1 {
+					homeSeed, awaySeed := seeds[2*position-2], seeds[2*position-1]
+					if homeSeed <= len(opts.TeamIDs) {
+						tie.HomeTeamID, tie.HomeSeed = &opts.TeamIDs[homeSeed-1], homeSeed
+					}
+					if awaySeed <= len(opts.TeamIDs) {
+						tie.AwayTeamID, tie.AwaySeed = &opts.TeamIDs[awaySeed-1], awaySeed
+					}
+					opening = append(opening, tie)
+				}
+				if err := brackets.CreateTie(tie); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, tie := range opening {
+			switch {
+			case tie.HomeTeamID != nil && tie.AwayTeamID != nil:
+				if err := scheduleTie(repo, tie); err != nil {
+					return err
+				}
+			case tie.HomeTeamID != nil:
+				// Bye: the seeded team goes straight through
+				if err := promoteWinner(repo, brackets, tie, *tie.HomeTeamID); err != nil {
+					return err
+				}
+			case tie.AwayTeamID != nil:
+				if err := promoteWinner(repo, brackets, tie, *tie.AwayTeamID); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetBracket(competitionID, opts.SeasonID)
+}
+
+func (s *bracketService) GetBracket(competitionID uint, seasonID *uint) (*Bracket, error) {
+	ties, err := s.repo.FindByCompetition(competitionID, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ties) == 0 {
+		return nil, errors.New("bracket not found")
+	}
+
+	totalRounds := ties[len(ties)-1].Round
+	bracket := &Bracket{
+		CompetitionID: competitionID,
+		SeasonID:      seasonID,
+	}
+
+	ids := make(map[[2]int]uint, len(ties))
+	for _, tie := range ties {
+		ids[[2]int{tie.Round, tie.Position}] = tie.ID
+	}
+
+	for _, tie := range ties {
+		if len(bracket.Rounds) < tie.Round {
+			bracket.Rounds = append(bracket.Rounds, BracketRound{
+				Round: tie.Round,
+				Name:  roundName(tie.Round, totalRounds),
+			})
+		}
+
+		item := BracketTie{
+			TieID:        tie.ID,
+			Position:     tie.Position,
+			TwoLegged:    tie.TwoLegged,
+			FirstLegAt:   tie.FirstLegAt,
+			SecondLegAt:  tie.SecondLegAt,
+			WinnerTeamID: tie.WinnerTeamID,
+			Bye:          tie.Round == 1 && (tie.HomeTeamID == nil || tie.AwayTeamID == nil),
+		}
+		if tie.HomeTeam != nil {
+			item.HomeTeam = &BracketTeam{TeamID: tie.HomeTeam.ID, Name: tie.HomeTeam.Name, Seed: tie.HomeSeed}
+		}
+		if tie.AwayTeam != nil {
+			item.AwayTeam = &BracketTeam{TeamID: tie.AwayTeam.ID, Name: tie.AwayTeam.Name, Seed: tie.AwaySeed}
+		}
+		if next, ok := ids[[2]int{tie.Round + 1, (tie.Position + 1) / 2}]; ok {
+			item.NextTieID = &next
+		}
+
+		for _, m := range tie.Matches {
+			item.Legs = append(item.Legs, BracketLeg{
+				MatchID:            m.ID,
+				Leg:                m.Leg,
+				MatchTime:          m.MatchTime.Format(time.RFC3339),
+				Status:             string(m.Status),
+				HomeTeamID:         m.HomeTeamID,
+				AwayTeamID:         m.AwayTeamID,
+				HomeScore:          m.HomeScore,
+				AwayScore:          m.AwayScore,
+				HomeExtraTimeScore: m.HomeExtraTimeScore,
+				AwayExtraTimeScore: m.AwayExtraTimeScore,
+				HomePenalties:      m.HomePenalties,
+				AwayPenalties:      m.AwayPenalties,
+			})
+		}
+		if tie.TwoLegged {
+			item.AggregateHome, item.AggregateAway = aggregateScore(tie)
+		}
+
+		if tie.Round == totalRounds {
+			bracket.ChampionID = tie.WinnerTeamID
+		}
+
+		round := &bracket.Rounds[tie.Round-1]
+		round.Ties = append(round.Ties, item)
+	}
+
+	return bracket, nil
+}
+
+// seedOrder returns the seeds of a bracket of the given size in slot order,
+// e.g. 8 -> [1 8 4 5 2 7 3 6], so the top seeds can only meet late on.
+func seedOrder(size int) []int {
+	order := []int{1, 2}
+	for n := 4; n <= size; n *= 2 {
+		next := make([]int, 0, n)
+		for _, seed := range order {
+			next = append(next, seed, n+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+func roundName(round, totalRounds int) string {
+	switch totalRounds - round {
+	case 0:
+		return "Final"
+	case 1:
+		return "Semi-finals"
+	case 2:
+		return "Quarter-finals"
+	default:
+		return fmt.Sprintf("Round of %d", 1<<(totalRounds-round+1))
+	}
+}
+
+// aggregateScore sums the legs played so far from the point of view of the
+// tie's home side (the home team of the first leg).
+func aggregateScore(tie models.CupTie) (*int, *int) {
+	var home, away int
+	played := false
+	for _, m := range tie.Matches {
+		if !m.Status.HasFinalResult() || m.HomeScore == nil || m.AwayScore == nil {
+			continue
+		}
+		played = true
+		if m.Leg == 1 {
+			home += *m.HomeScore
+			away += *m.AwayScore
+		} else {
+			home += *m.AwayScore
+			away += *m.HomeScore
+		}
+	}
+	if !played {
+		return nil, nil
+	}
+	return &home, &away
+}
+
+// advanceTie re-evaluates a tie after one of its legs has been reported and,
+// once it is decided, moves the winner into the next round.
+func advanceTie(repo repositories.MatchRepository, tieID uint) error {
+	brackets := repositories.NewBracketRepository(repo.GetDB())
+
+	tie, err := brackets.FindTieByID(tieID)
+	if err != nil {
+		return err
+	}
+
+	winner, err := tieWinner(tie)
+	if err != nil || winner == nil {
+		return err
+	}
+
+	return promoteWinner(repo, brackets, tie, *winner)
+}
+
+// tieWinner works out who won a tie. It returns nil while legs are still to
+// be played, and an error when the reported results cannot settle the tie.
+func tieWinner(tie *models.CupTie) (*uint, error) {
+	legs := 1
+	if tie.TwoLegged {
+		legs = 2
+	}
+	if len(tie.Matches) < legs {
+		return nil, nil
+	}
+
+	for i, m := range tie.Matches[:legs] {
+		if !m.Status.HasFinalResult() || m.HomeScore == nil || m.AwayScore == nil {
+			return nil, nil
+		}
+		if i < legs-1 && (m.HomeExtraTimeScore != nil || m.HomePenalties != nil) {
+			return nil, errors.New("extra time and penalties can only be played in the deciding leg")
+		}
+	}
+
+	first, last := tie.Matches[0], tie.Matches[legs-1]
+	homeTeam, awayTeam := first.HomeTeamID, first.AwayTeamID
+
+	home, away := *first.HomeScore, *first.AwayScore
+	if tie.TwoLegged {
+		home += *last.AwayScore
+		away += *last.HomeScore
+
+		// Away goals: the tie's home side scored away in the second leg
+		if home == away && tie.AwayGoalsRule {
+			home, away = *last.AwayScore, *first.AwayScore
+		}
+	}
+
+	hasPenalties := last.HomePenalties != nil && last.AwayPenalties != nil
+	switch {
+	case home > away && !hasPenalties:
+		return &homeTeam, nil
+	case away > home && !hasPenalties:
+		return &awayTeam, nil
+	case home != away:
+		return nil, errors.New("a penalty shootout is only allowed when the tie is level")
+	case !hasPenalties:
+		return nil, errors.New("the tie is level: a penalty shootout result is required")
+	}
+
+	if *last.HomePenalties > *last.AwayPenalties {
+		return &last.HomeTeamID, nil
+	}
+	return &last.AwayTeamID, nil
+}
+
+// promoteWinner records the winner of a tie and places them in their slot of
+// the next round, scheduling that tie once both sides are known.
+func promoteWinner(repo repositories.MatchRepository, brackets repositories.BracketRepository, tie *models.CupTie, winner uint) error {
+	if tie.WinnerTeamID != nil && *tie.WinnerTeamID == winner {
+		return nil
+	}
+
+	tie.WinnerTeamID = &winner
+	if err := brackets.UpdateTie(tie); err != nil {
+		return err
+	}
+
+	next, err := brackets.FindTie(tie.CompetitionID, tie.SeasonID, tie.Round+1, (tie.Position+1)/2)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// That was the final
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, m := range next.Matches {
+		if !m.Status.IsEditable() {
+			return errors.New("cannot change the winner of a tie whose next round has already been played")
+		}
+	}
+
+	seed := tie.AwaySeed
+	if tie.HomeTeamID != nil && *tie.HomeTeamID == winner {
+		seed = tie.HomeSeed
+	}
+	if tie.Position%2 == 1 {
+		next.HomeTeamID, next.HomeSeed = &winner, seed
+	} else {
+		next.AwayTeamID, next.AwaySeed = &winner, seed
+	}
+	if err := brackets.UpdateTie(next); err != nil {
+		return err
+	}
+
+	if next.HomeTeamID == nil || next.AwayTeamID == nil {
+		return nil
+	}
+	return scheduleTie(repo, next)
+}
+
+// scheduleTie creates the matches of a tie, or updates the teams of matches
+// already created when a previous-round result was corrected.
+func scheduleTie(repo repositories.MatchRepository, tie *models.CupTie) error {
+	legs := []models.Match{{
+		MatchTime:  tie.FirstLegAt,
+		HomeTeamID: *tie.HomeTeamID,
+		AwayTeamID: *tie.AwayTeamID,
+		Leg:        1,
+	}}
+	if tie.TwoLegged && tie.SecondLegAt != nil {
+		legs = append(legs, models.Match{
+			MatchTime:  *tie.SecondLegAt,
+			HomeTeamID: *tie.AwayTeamID,
+			AwayTeamID: *tie.HomeTeamID,
+			Leg:        2,
+		})
+	}
+
+	for i, leg := range legs {
+		if i < len(tie.Matches) {
+			existing := tie.Matches[i]
+			existing.HomeTeamID = leg.HomeTeamID
+			existing.AwayTeamID = leg.AwayTeamID
+			if err := repo.Update(&existing); err != nil {
+				return err
+			}
+			continue
+		}
+
+		competitionID := tie.CompetitionID
+		tieID := tie.ID
+		leg.SeasonID = tie.SeasonID
+		leg.CompetitionID = &competitionID
+		leg.TieID = &tieID
+		leg.Matchday = tie.Round
+		leg.Status = models.Scheduled
+		if err := repo.Create(&leg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"xyz-football/internal/models"
+)
+
+func TestSeedOrder(t *testing.T) {
+	tests := []struct {
+		size int
+		want []int
+	}{
+		{2, []int{1, 2}},
+		{4, []int{1, 4, 2, 3}},
+		{8, []int{1, 8, 4, 5, 2, 7, 3, 6}},
+		{16, []int{1, 16, 8, 9, 4, 13, 5, 12, 2, 15, 7, 10, 3, 14, 6, 11}},
+	}
+
+	for _, tt := range tests {
+		if got := seedOrder(tt.size); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("seedOrder(%d) = %v, want %v", tt.size, got, tt.want)
+		}
+	}
+}
+
+// TestSeedOrderByes checks that the seeds without an opponent in the opening
+// round, when the field does not fill the bracket, are the top ones.
+func TestSeedOrderByes(t *testing.T) {
+	tests := []struct {
+		teams int
+		size  int
+		byes  []int
+	}{
+		{teams: 4, size: 4, byes: []int{}},
+		{teams: 3, size: 4, byes: []int{1}},
+		{teams: 6, size: 8, byes: []int{1, 2}},
+		{teams: 5, size: 8, byes: []int{1, 2, 3}},
+		{teams: 12, size: 16, byes: []int{1, 4, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		order := seedOrder(tt.size)
+		byes := []int{}
+		for i := 0; i < len(order); i += 2 {
+			home, away := order[i], order[i+1]
+			if home > tt.teams && away > tt.teams {
+				t.Errorf("%d teams in %d: tie %d has no team at all", tt.teams, tt.size, i/2+1)
+			}
+			if away > tt.teams {
+				byes = append(byes, home)
+			}
+		}
+		if !reflect.DeepEqual(byes, tt.byes) {
+			t.Errorf("%d teams in %d: byes for seeds %v, want %v", tt.teams, tt.size, byes, tt.byes)
+		}
+	}
+}
+
+func TestTieWinner(t *testing.T) {
+	score := func(v int) *int { return &v }
+
+	// leg is a finished match of a tie between teams 1 and 2, with team 1 at
+	// home in the first leg
+	leg := func(n, home, away int) models.Match {
+		m := models.Match{Leg: n, Status: models.Finished, HomeScore: score(home), AwayScore: score(away)}
+		m.HomeTeamID, m.AwayTeamID = 1, 2
+		if n == 2 {
+			m.HomeTeamID, m.AwayTeamID = 2, 1
+		}
+		return m
+	}
+	withPenalties := func(m models.Match, home, away int) models.Match {
+		m.HomePenalties, m.AwayPenalties = score(home), score(away)
+		return m
+	}
+	withExtraTime := func(m models.Match, home, away int) models.Match {
+		m.HomeExtraTimeScore, m.AwayExtraTimeScore = score(home), score(away)
+		return m
+	}
+	unplayed := func(n int) models.Match {
+		m := leg(n, 0, 0)
+		m.Status, m.HomeScore, m.AwayScore = models.Scheduled, nil, nil
+		return m
+	}
+
+	team := func(id uint) *uint { return &id }
+
+	tests := []struct {
+		name    string
+		tie     models.CupTie
+		want    *uint
+		wantErr bool
+	}{
+		{
+			name: "single leg home win",
+			tie:  models.CupTie{Matches: []models.Match{leg(1, 2, 0)}},
+			want: team(1),
+		},
+		{
+			name: "single leg away win",
+			tie:  models.CupTie{Matches: []models.Match{leg(1, 1, 3)}},
+			want: team(2),
+		},
+		{
+			name: "single leg not played yet",
+			tie:  models.CupTie{Matches: []models.Match{unplayed(1)}},
+		},
+		{
+			name: "single leg won on penalties",
+			tie:  models.CupTie{Matches: []models.Match{withPenalties(leg(1, 1, 1), 3, 4)}},
+			want: team(2),
+		},
+		{
+			name:    "level without a shootout",
+			tie:     models.CupTie{Matches: []models.Match{leg(1, 1, 1)}},
+			wantErr: true,
+		},
+		{
+			name:    "shootout although the tie is not level",
+			tie:     models.CupTie{Matches: []models.Match{withPenalties(leg(1, 2, 1), 4, 3)}},
+			wantErr: true,
+		},
+		{
+			name: "second leg still to be played",
+			tie:  models.CupTie{TwoLegged: true, Matches: []models.Match{leg(1, 3, 0), unplayed(2)}},
+		},
+		{
+			name: "won on aggregate",
+			tie:  models.CupTie{TwoLegged: true, Matches: []models.Match{leg(1, 2, 1), leg(2, 1, 1)}},
+			want: team(1),
+		},
+		{
+			name: "lost on aggregate after winning the first leg",
+			tie:  models.CupTie{TwoLegged: true, Matches: []models.Match{leg(1, 1, 0), leg(2, 3, 1)}},
+			want: team(2),
+		},
+		{
+			name: "away goals",
+			tie:  models.CupTie{TwoLegged: true, AwayGoalsRule: true, Matches: []models.Match{leg(1, 0, 1), leg(2, 1, 2)}},
+			want: team(1),
+		},
+		{
+			name:    "level on aggregate without the away goals rule needs a shootout",
+			tie:     models.CupTie{TwoLegged: true, Matches: []models.Match{leg(1, 0, 1), leg(2, 1, 2)}},
+			wantErr: true,
+		},
+		{
+			name: "level on away goals too, decided on penalties in the second leg",
+			tie: models.CupTie{TwoLegged: true, AwayGoalsRule: true, Matches: []models.Match{
+				leg(1, 1, 1),
+				withPenalties(withExtraTime(leg(2, 1, 1), 1, 1), 5, 4),
+			}},
+			want: team(2),
+		},
+		{
+			name: "penalties in the first leg",
+			tie: models.CupTie{TwoLegged: true, Matches: []models.Match{
+				withPenalties(leg(1, 1, 1), 5, 4),
+				leg(2, 0, 0),
+			}},
+			wantErr: true,
+		},
+		{
+			name: "extra time in the first leg",
+			tie: models.CupTie{TwoLegged: true, Matches: []models.Match{
+				withExtraTime(leg(1, 1, 1), 2, 1),
+				leg(2, 0, 0),
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tieWinner(&tt.tie)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tieWinner() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tieWinner() = %v, want %v", derefUint(got), derefUint(tt.want))
+			}
+		})
+	}
+}
+
+func derefUint(id *uint) interface{} {
+	if id == nil {
+		return nil
+	}
+	return *id
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
+)
+
+type CompetitionService interface {
+	CreateCompetition(competition *models.Competition) error
+	GetAllCompetitions() ([]models.Competition, error)
+	GetCompetitionByID(id uint) (*models.Competition, error)
+	UpdateCompetition(competition *models.Competition) error
+	DeleteCompetition(id uint) error
+}
+
+type competitionService struct {
+	repo repositories.CompetitionRepository
+}
+
+func NewCompetitionService(repo repositories.CompetitionRepository) CompetitionService {
+	return &competitionService{repo: repo}
+}
+
+func (s *competitionService) CreateCompetition(competition *models.Competition) error {
+	// Default to a league if no type provided
+	if competition.Type == "" {
+		competition.Type = models.League
+	}
+
+	if err := validateStandingsRules(competition.Rules); err != nil {
+		return err
+	}
+
+	if err := validateDisciplineRules(competition.Discipline); err != nil {
+		return err
+	}
+
+	return s.repo.Create(competition)
+}
+
+func (s *competitionService) GetAllCompetitions() ([]models.Competition, error) {
+	return s.repo.FindAll()
+}
+
+func (s *competitionService) GetCompetitionByID(id uint) (*models.Competition, error) {
+	return s.repo.FindByID(id)
+}
+
+func (s *competitionService) UpdateCompetition(competition *models.Competition) error {
+	if competition.Type == "" {
+		competition.Type = models.League
+	}
+
+	if err := validateStandingsRules(competition.Rules); err != nil {
+		return err
+	}
+
+	if err := validateDisciplineRules(competition.Discipline); err != nil {
+		return err
+	}
+
+	return s.repo.Update(competition)
+}
+
+func (s *competitionService) DeleteCompetition(id uint) error {
+	// Check if competition exists
+	if _, err := s.repo.FindByID(id); err != nil {
+		return errors.New("competition not found")
+	}
+
+	return s.repo.Delete(id)
+}
+
+func validateStandingsRules(rules *models.StandingsRules) error {
+	if rules == nil {
+		return nil
+	}
+
+	if rules.PointsForWin <= rules.PointsForDraw || rules.PointsForDraw < rules.PointsForLoss {
+		return errors.New("points must rank a win above a draw and a draw at least as high as a loss")
+	}
+
+	seen := make(map[models.TieBreaker]bool, len(rules.TieBreakers))
+	for _, tb := range rules.TieBreakers {
+		if seen[tb] {
+			return fmt.Errorf("tie-breaker %s is listed more than once", tb)
+		}
+		seen[tb] = true
+	}
+
+	return nil
+}
+
+func validateDisciplineRules(rules *models.DisciplineRules) error {
+	if rules == nil {
+		return nil
+	}
+
+	for i := 1; i < len(rules.YellowCardThresholds); i++ {
+		if rules.YellowCardThresholds[i].Yellows <= rules.YellowCardThresholds[i-1].Yellows {
+			return errors.New("yellow card thresholds must be listed in increasing order")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
+)
+
+// Suspension is a ban a player picked up in one competition and season.
+type Suspension struct {
+	PlayerID       uint      `json:"player_id"`
+	TeamID         uint      `json:"team_id"`
+	CompetitionID  *uint     `json:"competition_id,omitempty"`
+	SeasonID       *uint     `json:"season_id,omitempty"`
+	Reason         string    `json:"reason"`
+	TriggerMatchID uint      `json:"trigger_match_id"`
+	TriggeredAt    time.Time `json:"triggered_at"`
+	Matches        int       `json:"matches"`
+	ServedMatchIDs []uint    `json:"served_match_ids"`
+	Pending        int       `json:"pending"`
+
+	scope disciplineScope
+}
+
+// disciplineScope is the competition and season bookings accumulate in;
+// zero stands for matches without one.
+type disciplineScope struct {
+	competitionID uint
+	seasonID      uint
+}
+
+func scopeOf(match models.Match) disciplineScope {
+	var scope disciplineScope
+	if match.CompetitionID != nil {
+		scope.competitionID = *match.CompetitionID
+	}
+	if match.SeasonID != nil {
+		scope.seasonID = *match.SeasonID
+	}
+	return scope
+}
+
+// disciplineRules returns the suspension thresholds of a competition, or the
+// defaults when it has none configured.
+func disciplineRules(competition *models.Competition) models.DisciplineRules {
+	if competition == nil || competition.Discipline == nil {
+		return models.DefaultDisciplineRules()
+	}
+	return *competition.Discipline
+}
+
+// computeSuspensions works out the bans the cards lead to, in the order they
+// were triggered, and serves them with the matches that follow. cards must be
+// in chronological order with their match and its competition loaded, matches
+// in chronological order. A match serves a ban once it has a final result;
+// servingMatchID counts as well, so eligibility can be checked for a match
+// that is about to be reported. Bookings in friendlies never lead to a ban.
+func computeSuspensions(cards []models.Card, matches []models.Match, servingMatchID uint) []Suspension {
+	// All cards a player got in one match are looked at together
+	type playerMatch struct {
+		playerID uint
+		matchID  uint
+	}
+	var order []playerMatch
+	booked := make(map[playerMatch][]models.Card)
+	for _, card := range cards {
+		if card.Match.Competition != nil && card.Match.Competition.Type == models.Friendly {
+			continue
+		}
+		key := playerMatch{card.PlayerID, card.MatchID}
+		if _, ok := booked[key]; !ok {
+			order = append(order, key)
+		}
+		booked[key] = append(booked[key], card)
+	}
+
+	type playerScope struct {
+		playerID uint
+		scope    disciplineScope
+	}
+	yellows := make(map[playerScope]int)
+	var suspensions []Suspension
+
+	for _, key := range order {
+		matchCards := booked[key]
+		match := matchCards[0].Match
+		rules := disciplineRules(match.Competition)
+		tally := playerScope{key.playerID, scopeOf(match)}
+
+		ban := func(reason string, length int) {
+			if length <= 0 {
+				return
+			}
+			suspensions = append(suspensions, Suspension{
+				PlayerID:       key.playerID,
+				TeamID:         matchCards[0].TeamID,
+				CompetitionID:  match.CompetitionID,
+				SeasonID:       match.SeasonID,
+				Reason:         reason,
+				TriggerMatchID: match.ID,
+				TriggeredAt:    match.MatchTime,
+				Matches:        length,
+				ServedMatchIDs: []uint{},
+				Pending:        length,
+				scope:          tally.scope,
+			})
+		}
+
+		// The first booking of a player sent off for two yellows is not
+		// counted towards accumulation
+		secondYellow := false
+		for _, card := range matchCards {
+			if card.Type == models.SecondYellowCard {
+				secondYellow = true
+			}
+		}
+
+		for _, card := range matchCards {
+			switch card.Type {
+			case models.YellowCard:
+				if secondYellow {
+					continue
+				}
+				before := yellows[tally]
+				yellows[tally]++
+				for _, threshold := range rules.YellowCardThresholds {
+					if before < threshold.Yellows && yellows[tally] >= threshold.Yellows {
+						ban(fmt.Sprintf("%d yellow cards", threshold.Yellows), threshold.Matches)
+					}
+				}
+			case models.SecondYellowCard:
+				ban("sent off for a second yellow card", rules.SecondYellowBan)
+			case models.RedCard:
+				ban("sent off", rules.RedCardBan)
+			}
+		}
+	}
+
+	serveSuspensions(suspensions, matches, servingMatchID)
+	return suspensions
+}
+
+// serveSuspensions counts every match a team plays after a ban was triggered
+// towards the bans of its players in the same competition and season, the
+// oldest ban first.
+func serveSuspensions(suspensions []Suspension, matches []models.Match, servingMatchID uint) {
+	for _, match := range matches {
+		if match.ID != servingMatchID && !match.Status.HasFinalResult() {
+			continue
+		}
+
+		scope := scopeOf(match)
+		served := make(map[uint]bool)
+		for i := range suspensions {
+			suspension := &suspensions[i]
+			if suspension.Pending == 0 || served[suspension.PlayerID] || suspension.scope != scope {
+				continue
+			}
+			if suspension.TeamID != match.HomeTeamID && suspension.TeamID != match.AwayTeamID {
+				continue
+			}
+			if !match.MatchTime.After(suspension.TriggeredAt) {
+				continue
+			}
+
+			suspension.ServedMatchIDs = append(suspension.ServedMatchIDs, match.ID)
+			suspension.Pending--
+			served[suspension.PlayerID] = true
+		}
+	}
+}
+
+// suspendedPlayers returns the players serving a ban in the given match,
+// with the reason for it.
+func suspendedPlayers(matchRepo repositories.MatchRepository, cardRepo repositories.CardRepository, match *models.Match) (map[uint]string, error) {
+	if match.Competition != nil && match.Competition.Type == models.Friendly {
+		return nil, nil
+	}
+
+	filter := repositories.MatchFilter{SeasonID: match.SeasonID, CompetitionID: match.CompetitionID}
+	cards, err := cardRepo.FindByFilter(repositories.CardFilter{MatchFilter: filter})
+	if err != nil {
+		return nil, err
+	}
+	matches, err := matchRepo.FindByFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	suspended := make(map[uint]string)
+	for _, suspension := range computeSuspensions(cards, matches, match.ID) {
+		for _, id := range suspension.ServedMatchIDs {
+			if id == match.ID {
+				suspended[suspension.PlayerID] = suspension.Reason
+			}
+		}
+	}
+	return suspended, nil
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
+)
+
+type DisciplineService interface {
+	GetPlayerDiscipline(playerID uint, filter ReportFilter) (*PlayerDiscipline, error)
+	GetDisciplineReport(filter ReportFilter) ([]PlayerDiscipline, error)
+}
+
+type disciplineService struct {
+	cardRepo   repositories.CardRepository
+	matchRepo  repositories.MatchRepository
+	playerRepo repositories.PlayerRepository
+}
+
+// PlayerDiscipline is a player's booking record with the suspensions it led
+// to. Suspended is set while any of them still has matches pending.
+type PlayerDiscipline struct {
+	PlayerID       uint         `json:"player_id"`
+	PlayerName     string       `json:"player_name"`
+	TeamName       string       `json:"team_name"`
+	Yellows        int          `json:"yellows"`
+	SecondYellows  int          `json:"second_yellows"`
+	Reds           int          `json:"reds"`
+	Suspended      bool         `json:"suspended"`
+	PendingMatches int          `json:"pending_matches"`
+	Bookings       []Booking    `json:"bookings"`
+	Suspensions    []Suspension `json:"suspensions"`
+}
+
+type Booking struct {
+	MatchID   uint   `json:"match_id"`
+	MatchTime string `json:"match_time"`
+	Opponent  string `json:"opponent"`
+	Type      string `json:"type"`
+	Minute    int    `json:"minute"`
+	AddedTime int    `json:"added_time,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func NewDisciplineService(
+	cardRepo repositories.CardRepository,
+	matchRepo repositories.MatchRepository,
+	playerRepo repositories.PlayerRepository,
+) DisciplineService {
+	return &disciplineService{
+		cardRepo:   cardRepo,
+		matchRepo:  matchRepo,
+		playerRepo: playerRepo,
+	}
+}
+
+func (s *disciplineService) GetPlayerDiscipline(playerID uint, filter ReportFilter) (*PlayerDiscipline, error) {
+	player, err := s.playerRepo.FindByID(playerID)
+	if err != nil {
+		return nil, errors.New("player not found")
+	}
+
+	records, err := s.disciplineRecords(filter, &playerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return &PlayerDiscipline{
+			PlayerID:    player.ID,
+			PlayerName:  player.Name,
+			TeamName:    player.Team.Name,
+			Bookings:    []Booking{},
+			Suspensions: []Suspension{},
+		}, nil
+	}
+
+	return &records[0], nil
+}
+
+func (s *disciplineService) GetDisciplineReport(filter ReportFilter) ([]PlayerDiscipline, error) {
+	records, err := s.disciplineRecords(filter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Suspended players first, then the worst records
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		switch {
+		case a.PendingMatches != b.PendingMatches:
+			return a.PendingMatches > b.PendingMatches
+		case a.Reds != b.Reds:
+			return a.Reds > b.Reds
+		case a.SecondYellows != b.SecondYellows:
+			return a.SecondYellows > b.SecondYellows
+		case a.Yellows != b.Yellows:
+			return a.Yellows > b.Yellows
+		}
+		return a.PlayerName < b.PlayerName
+	})
+
+	return records, nil
+}
+
+// disciplineRecords builds the record of every player booked in scope.
+func (s *disciplineService) disciplineRecords(filter ReportFilter, playerID *uint) ([]PlayerDiscipline, error) {
+	cards, err := s.cardRepo.FindByFilter(repositories.CardFilter{
+		MatchFilter: filter.matchFilter(),
+		PlayerID:    playerID,
+		TeamID:      filter.TeamID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.matchRepo.FindByFilter(filter.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	var order []uint
+	records := make(map[uint]*PlayerDiscipline)
+	for _, card := range cards {
+		record, ok := records[card.PlayerID]
+		if !ok {
+			record = &PlayerDiscipline{
+				PlayerID:    card.PlayerID,
+				PlayerName:  card.Player.Name,
+				Bookings:    []Booking{},
+				Suspensions: []Suspension{},
+			}
+			records[card.PlayerID] = record
+			order = append(order, card.PlayerID)
+		}
+
+		// Cards are in chronological order, so the last one names the current team
+		team, opponent := card.Match.HomeTeam.Name, card.Match.AwayTeam.Name
+		if card.TeamID == card.Match.AwayTeamID {
+			team, opponent = opponent, team
+		}
+		record.TeamName = team
+
+		switch card.Type {
+		case models.YellowCard:
+			record.Yellows++
+		case models.SecondYellowCard:
+			record.SecondYellows++
+		case models.RedCard:
+			record.Reds++
+		}
+
+		record.Bookings = append(record.Bookings, Booking{
+			MatchID:   card.MatchID,
+			MatchTime: card.Match.MatchTime.Format(time.RFC3339),
+			Opponent:  opponent,
+			Type:      string(card.Type),
+			Minute:    card.Minute,
+			AddedTime: card.AddedTime,
+			Reason:    card.Reason,
+		})
+	}
+
+	for _, suspension := range computeSuspensions(cards, matches, 0) {
+		record := records[suspension.PlayerID]
+		record.Suspensions = append(record.Suspensions, suspension)
+		record.PendingMatches += suspension.Pending
+		record.Suspended = record.PendingMatches > 0
+	}
+
+	result := make([]PlayerDiscipline, 0, len(order))
+	for _, id := range order {
+		result = append(result, *records[id])
+	}
+	return result, nil
+}
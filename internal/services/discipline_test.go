@@ -0,0 +1,132 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"xyz-football/internal/models"
+)
+
+func TestComputeSuspensions(t *testing.T) {
+	kickOff := time.Date(2025, 8, 1, 15, 0, 0, 0, time.UTC)
+	league, cup := uint(1), uint(2)
+
+	// fixture is a match of team 1 a week after the previous one
+	fixture := func(id uint, status models.MatchStatus, competitionID *uint) models.Match {
+		return models.Match{
+			ID:            id,
+			HomeTeamID:    1,
+			AwayTeamID:    2,
+			MatchTime:     kickOff.AddDate(0, 0, 7*int(id)),
+			Status:        status,
+			CompetitionID: competitionID,
+		}
+	}
+	booking := func(match models.Match, cardType models.CardType) models.Card {
+		return models.Card{MatchID: match.ID, PlayerID: 7, TeamID: 1, Type: cardType, Match: match}
+	}
+	played := func(n int) []models.Match {
+		matches := make([]models.Match, n)
+		for i := range matches {
+			matches[i] = fixture(uint(i+1), models.Finished, &league)
+		}
+		return matches
+	}
+
+	friendly := fixture(1, models.Finished, nil)
+	friendly.Competition = &models.Competition{Type: models.Friendly}
+
+	type ban struct {
+		reason  string
+		trigger uint
+		served  []uint
+		pending int
+	}
+
+	tests := []struct {
+		name    string
+		cards   []models.Card
+		matches []models.Match
+		serving uint
+		want    []ban
+	}{
+		{
+			name:    "no cards",
+			matches: played(3),
+			want:    []ban{},
+		},
+		{
+			name:    "red card is served in the next match",
+			cards:   []models.Card{booking(played(1)[0], models.RedCard)},
+			matches: played(3),
+			want:    []ban{{"sent off", 1, []uint{2}, 0}},
+		},
+		{
+			name:    "ban stays pending until the next match has a result",
+			cards:   []models.Card{booking(played(1)[0], models.RedCard)},
+			matches: []models.Match{fixture(1, models.Finished, &league), fixture(2, models.Scheduled, &league)},
+			want:    []ban{{"sent off", 1, []uint{}, 1}},
+		},
+		{
+			name:    "match about to be reported counts as served",
+			cards:   []models.Card{booking(played(1)[0], models.RedCard)},
+			matches: []models.Match{fixture(1, models.Finished, &league), fixture(2, models.Scheduled, &league)},
+			serving: 2,
+			want:    []ban{{"sent off", 1, []uint{2}, 0}},
+		},
+		{
+			name: "fifth yellow card",
+			cards: []models.Card{
+				booking(played(5)[0], models.YellowCard),
+				booking(played(5)[1], models.YellowCard),
+				booking(played(5)[2], models.YellowCard),
+				booking(played(5)[3], models.YellowCard),
+				booking(played(5)[4], models.YellowCard),
+			},
+			matches: played(6),
+			want:    []ban{{"5 yellow cards", 5, []uint{6}, 0}},
+		},
+		{
+			name: "yellow before a second yellow is not accumulated",
+			cards: []models.Card{
+				booking(played(5)[0], models.YellowCard),
+				booking(played(5)[0], models.SecondYellowCard),
+				booking(played(5)[1], models.YellowCard),
+				booking(played(5)[2], models.YellowCard),
+				booking(played(5)[3], models.YellowCard),
+				booking(played(5)[4], models.YellowCard),
+			},
+			matches: played(6),
+			want:    []ban{{"sent off for a second yellow card", 1, []uint{2}, 0}},
+		},
+		{
+			name:    "friendlies never lead to a ban",
+			cards:   []models.Card{booking(friendly, models.RedCard)},
+			matches: []models.Match{friendly, fixture(2, models.Finished, nil)},
+			want:    []ban{},
+		},
+		{
+			name:  "bans are only served in the same competition",
+			cards: []models.Card{booking(played(1)[0], models.RedCard)},
+			matches: []models.Match{
+				fixture(1, models.Finished, &league),
+				fixture(2, models.Finished, &cup),
+				fixture(3, models.Finished, &league),
+			},
+			want: []ban{{"sent off", 1, []uint{3}, 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []ban{}
+			for _, s := range computeSuspensions(tt.cards, tt.matches, tt.serving) {
+				got = append(got, ban{s.Reason, s.TriggerMatchID, s.ServedMatchIDs, s.Pending})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("computeSuspensions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
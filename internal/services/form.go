@@ -0,0 +1,139 @@
+package services
+
+import (
+	"strconv"
+
+	"xyz-football/internal/models"
+)
+
+// defaultFormLength is the number of matches shown in a form string.
+const defaultFormLength = 5
+
+// Outcome letters used in form strings and streaks.
+const (
+	outcomeWin  = 'W'
+	outcomeDraw = 'D'
+	outcomeLoss = 'L'
+)
+
+// teamResult is a finished match seen from one of the two teams. A tie
+// settled on penalties counts as a draw, as it does in the standings.
+type teamResult struct {
+	match        models.Match
+	home         bool
+	goalsFor     int
+	goalsAgainst int
+	outcome      byte
+}
+
+func (r teamResult) margin() int {
+	return r.goalsFor - r.goalsAgainst
+}
+
+// teamResults picks the matches of a team that have a final result, keeping
+// their order; callers pass matches sorted by kick-off.
+func teamResults(matches []models.Match, teamID uint) []teamResult {
+	var results []teamResult
+	for _, match := range matches {
+		if !match.Status.HasFinalResult() || match.HomeScore == nil || match.AwayScore == nil {
+			continue
+		}
+
+		var r teamResult
+		switch teamID {
+		case match.HomeTeamID:
+			r = teamResult{match: match, home: true, goalsFor: *match.HomeScore, goalsAgainst: *match.AwayScore}
+		case match.AwayTeamID:
+			r = teamResult{match: match, goalsFor: *match.AwayScore, goalsAgainst: *match.HomeScore}
+		default:
+			continue
+		}
+
+		switch {
+		case r.goalsFor > r.goalsAgainst:
+			r.outcome = outcomeWin
+		case r.goalsFor < r.goalsAgainst:
+			r.outcome = outcomeLoss
+		default:
+			r.outcome = outcomeDraw
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// TeamRecord sums up a run of results of one team.
+type TeamRecord struct {
+	Played         int `json:"played"`
+	Won            int `json:"won"`
+	Drawn          int `json:"drawn"`
+	Lost           int `json:"lost"`
+	GoalsFor       int `json:"goals_for"`
+	GoalsAgainst   int `json:"goals_against"`
+	GoalDifference int `json:"goal_difference"`
+	CleanSheets    int `json:"clean_sheets"`
+	FailedToScore  int `json:"failed_to_score"`
+}
+
+func (r *TeamRecord) add(result teamResult) {
+	r.Played++
+	switch result.outcome {
+	case outcomeWin:
+		r.Won++
+	case outcomeDraw:
+		r.Drawn++
+	case outcomeLoss:
+		r.Lost++
+	}
+	r.GoalsFor += result.goalsFor
+	r.GoalsAgainst += result.goalsAgainst
+	r.GoalDifference = r.GoalsFor - r.GoalsAgainst
+	if result.goalsAgainst == 0 {
+		r.CleanSheets++
+	}
+	if result.goalsFor == 0 {
+		r.FailedToScore++
+	}
+}
+
+// formString lists the outcomes of the last n results, oldest first, e.g. "WWDLW".
+func formString(results []teamResult, n int) string {
+	if len(results) > n {
+		results = results[len(results)-n:]
+	}
+	form := make([]byte, len(results))
+	for i, r := range results {
+		form[i] = r.outcome
+	}
+	return string(form)
+}
+
+// currentStreak describes the run the team is on, e.g. "W3", or "" before
+// its first result.
+func currentStreak(results []teamResult) string {
+	if len(results) == 0 {
+		return ""
+	}
+	last := results[len(results)-1].outcome
+	n := 0
+	for i := len(results) - 1; i >= 0 && results[i].outcome == last; i-- {
+		n++
+	}
+	return string(last) + strconv.Itoa(n)
+}
+
+// longestRun is the longest sequence of consecutive results that all match.
+func longestRun(results []teamResult, match func(teamResult) bool) int {
+	longest, run := 0, 0
+	for _, r := range results {
+		if !match(r) {
+			run = 0
+			continue
+		}
+		run++
+		if run > longest {
+			longest = run
+		}
+	}
+	return longest
+}
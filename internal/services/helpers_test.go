@@ -0,0 +1,29 @@
+package services
+
+import (
+	"testing"
+
+	"xyz-football/internal/database"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB opens an empty, migrated in-memory database.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // every connection would get a database of its own
+	t.Cleanup(func() { sqlDB.Close() })
+	database.Migrate(db)
+	return db
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"errors"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
+	"xyz-football/pkg/utils"
+)
+
+type ImportService interface {
+	ImportTeams(rows []TeamImport, dryRun bool) (*ImportReport, error)
+	ImportPlayers(rows []PlayerImport, dryRun bool) (*ImportReport, error)
+	ImportMatches(rows []MatchImport, dryRun bool) (*ImportReport, error)
+}
+
+type importService struct {
+	repo            repositories.ImportRepository
+	teamRepo        repositories.TeamRepository
+	playerRepo      repositories.PlayerRepository
+	seasonRepo      repositories.SeasonRepository
+	competitionRepo repositories.CompetitionRepository
+}
+
+// ErrInvalidImport is returned when at least one row of an import is
+// invalid; the report lists the problems of every row and nothing is saved.
+var ErrInvalidImport = errors.New("import has invalid rows, nothing was saved")
+
+// TeamImport is one row of a team import. Errors holds the problems already
+// found while reading the row; the service adds its own.
+type TeamImport struct {
+	Team   models.Team
+	Errors ValidationErrors
+}
+
+// PlayerImport is one row of a player import. The team is given by
+// Player.TeamID or, when that is 0, by its name.
+type PlayerImport struct {
+	Player models.Player
+	Team   string
+	Errors ValidationErrors
+}
+
+// MatchImport is one row of a fixture import. Either team may be given by ID
+// or, when that is 0, by its name.
+type MatchImport struct {
+	Match    models.Match
+	HomeTeam string
+	AwayTeam string
+	Errors   ValidationErrors
+}
+
+// ImportReport sums up an import. Rows are numbered from 1 in the order they
+// were sent, not counting a CSV header. IDs lists the created records in the
+// same order and is empty for a dry run.
+type ImportReport struct {
+	DryRun  bool       `json:"dry_run"`
+	Total   int        `json:"total"`
+	Created int        `json:"created"`
+	IDs     []uint     `json:"ids"`
+	Errors  []RowError `json:"errors"`
+}
+
+// RowError lists the problems of one row of an import.
+type RowError struct {
+	Row    int              `json:"row"`
+	Errors ValidationErrors `json:"errors"`
+}
+
+func newImportReport(total int, dryRun bool) *ImportReport {
+	return &ImportReport{DryRun: dryRun, Total: total, IDs: []uint{}, Errors: []RowError{}}
+}
+
+func (r *ImportReport) reject(row int, errs ValidationErrors) {
+	if len(errs) > 0 {
+		r.Errors = append(r.Errors, RowError{Row: row + 1, Errors: errs})
+	}
+}
+
+func NewImportService(
+	repo repositories.ImportRepository,
+	teamRepo repositories.TeamRepository,
+	playerRepo repositories.PlayerRepository,
+	seasonRepo repositories.SeasonRepository,
+	competitionRepo repositories.CompetitionRepository,
+) ImportService {
+	return &importService{
+		repo:            repo,
+		teamRepo:        teamRepo,
+		playerRepo:      playerRepo,
+		seasonRepo:      seasonRepo,
+		competitionRepo: competitionRepo,
+	}
+}
+
+// ImportTeams creates every team or, when a row is invalid, none. Team names
+// must be unique, ignoring case and accents, as later imports refer to teams
+// by name.
+func (s *importService) ImportTeams(rows []TeamImport, dryRun bool) (*ImportReport, error) {
+	existing, err := s.teamRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[string]int, len(existing)+len(rows)) // folded name to row, 0 for a stored team
+	for _, team := range existing {
+		taken[utils.Fold(team.Name)] = 0
+	}
+
+	report := newImportReport(len(rows), dryRun)
+	teams := make([]models.Team, len(rows))
+	for i, row := range rows {
+		errs := row.Errors
+		key := utils.Fold(row.Team.Name)
+		if prev, ok := taken[key]; ok && key != "" {
+			if prev == 0 {
+				errs.add("name", "team %q already exists", row.Team.Name)
+			} else {
+				errs.add("name", "team %q is also in row %d", row.Team.Name, prev)
+			}
+		} else if key != "" {
+			taken[key] = i + 1
+		}
+		report.reject(i, errs)
+		teams[i] = row.Team
+	}
+
+	if len(report.Errors) > 0 {
+		return report, ErrInvalidImport
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	if err := s.repo.CreateTeams(teams); err != nil {
+		return nil, err
+	}
+	for _, team := range teams {
+		report.IDs = append(report.IDs, team.ID)
+	}
+	report.Created = len(teams)
+	return report, nil
+}
+
+// ImportPlayers creates every player, each with an open-ended contract at
+// their team, or none when a row is invalid. Shirt numbers must be unique
+// within a team, both against its squad and across the rows.
+func (s *importService) ImportPlayers(rows []PlayerImport, dryRun bool) (*ImportReport, error) {
+	teams, err := s.loadTeams()
+	if err != nil {
+		return nil, err
+	}
+
+	type shirt struct {
+		teamID uint
+		number int
+	}
+	shirts := make(map[shirt]int) // number to row, 0 for a stored player
+	squads := make(map[uint]bool)
+
+	report := newImportReport(len(rows), dryRun)
+	players := make([]models.Player, len(rows))
+	for i, row := range rows {
+		errs := row.Errors
+		player := row.Player
+		player.TeamID = teams.resolve("team", player.TeamID, row.Team, &errs)
+
+		if player.TeamID != 0 && player.Number != 0 {
+			if !squads[player.TeamID] {
+				squad, err := s.playerRepo.FindByTeam(player.TeamID)
+				if err != nil {
+					return nil, err
+				}
+				for _, p := range squad {
+					shirts[shirt{p.TeamID, p.Number}] = 0
+				}
+				squads[player.TeamID] = true
+			}
+
+			key := shirt{player.TeamID, player.Number}
+			if prev, ok := shirts[key]; !ok {
+				shirts[key] = i + 1
+			} else if prev == 0 {
+				errs.add("number", "player number already exists in this team")
+			} else {
+				errs.add("number", "number %d is also given to row %d", player.Number, prev)
+			}
+		}
+
+		report.reject(i, errs)
+		players[i] = player
+	}
+
+	if len(report.Errors) > 0 {
+		return report, ErrInvalidImport
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	if err := s.repo.CreatePlayers(players); err != nil {
+		return nil, err
+	}
+	for _, player := range players {
+		report.IDs = append(report.IDs, player.ID)
+	}
+	report.Created = len(players)
+	return report, nil
+}
+
+// ImportMatches schedules every fixture or none when a row is invalid. The
+// checks are those of a single new match.
+func (s *importService) ImportMatches(rows []MatchImport, dryRun bool) (*ImportReport, error) {
+	teams, err := s.loadTeams()
+	if err != nil {
+		return nil, err
+	}
+	scope := newScopeValidator(s.seasonRepo, s.competitionRepo)
+
+	report := newImportReport(len(rows), dryRun)
+	matches := make([]models.Match, len(rows))
+	for i, row := range rows {
+		errs := row.Errors
+		match := row.Match
+		match.HomeTeamID = teams.resolve("home_team", match.HomeTeamID, row.HomeTeam, &errs)
+		match.AwayTeamID = teams.resolve("away_team", match.AwayTeamID, row.AwayTeam, &errs)
+		if match.HomeTeamID != 0 && match.HomeTeamID == match.AwayTeamID {
+			errs.add("away_team", "home and away teams must be different")
+		}
+
+		errs = append(errs, scope.validate(&match)...)
+
+		match.Status = models.Scheduled
+		report.reject(i, errs)
+		matches[i] = match
+	}
+
+	if len(report.Errors) > 0 {
+		return report, ErrInvalidImport
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	if err := s.repo.CreateMatches(matches); err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		report.IDs = append(report.IDs, match.ID)
+	}
+	report.Created = len(matches)
+	return report, nil
+}
+
+// teamIndex finds stored teams by ID or by name, ignoring case and accents.
+type teamIndex struct {
+	byID   map[uint]bool
+	byName map[string][]uint
+}
+
+func (s *importService) loadTeams() (teamIndex, error) {
+	teams, err := s.teamRepo.FindAll()
+	if err != nil {
+		return teamIndex{}, err
+	}
+
+	index := teamIndex{byID: make(map[uint]bool, len(teams)), byName: make(map[string][]uint, len(teams))}
+	for _, team := range teams {
+		index.byID[team.ID] = true
+		key := utils.Fold(team.Name)
+		index.byName[key] = append(index.byName[key], team.ID)
+	}
+	return index, nil
+}
+
+// resolve returns the ID of the team a row refers to, or 0 after recording
+// why it could not be found. When both an ID and a name are given they have
+// to agree.
+func (t teamIndex) resolve(field string, id uint, name string, errs *ValidationErrors) uint {
+	if id == 0 && name == "" {
+		return 0 // already reported as missing
+	}
+
+	var byName uint
+	if name != "" {
+		ids := t.byName[utils.Fold(name)]
+		switch len(ids) {
+		case 0:
+			errs.add(field, "team %q not found", name)
+			return 0
+		case 1:
+			byName = ids[0]
+		default:
+			errs.add(field, "more than one team is named %q, give its ID instead", name)
+			return 0
+		}
+	}
+
+	switch {
+	case id == 0:
+		return byName
+	case !t.byID[id]:
+		errs.add(field+"_id", "team not found")
+		return 0
+	case byName != 0 && byName != id:
+		errs.add(field, "team %q does not match %s_id %d", name, field, id)
+		return 0
+	}
+	return id
+}
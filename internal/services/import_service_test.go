@@ -0,0 +1,237 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// newImportService runs the import service on an in-memory database holding
+// Alpha, with number 10 taken by a stored player, Beta and two teams both
+// named Gamma.
+func newImportService(t *testing.T) (ImportService, *gorm.DB, map[string]uint) {
+	t.Helper()
+
+	db := newTestDB(t)
+	ids := make(map[string]uint)
+	for _, name := range []string{"Alpha", "Beta", "Gamma", "Gámma"} {
+		team := &models.Team{Name: name}
+		if err := db.Create(team).Error; err != nil {
+			t.Fatal(err)
+		}
+		ids[name] = team.ID
+	}
+	player := &models.Player{TeamID: ids["Alpha"], Name: "Stored", Position: models.Striker, Number: 10}
+	if err := db.Create(player).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewImportService(
+		repositories.NewImportRepository(db),
+		repositories.NewTeamRepository(db),
+		repositories.NewPlayerRepository(db),
+		repositories.NewSeasonRepository(db),
+		repositories.NewCompetitionRepository(db),
+	)
+	return svc, db, ids
+}
+
+// rowErrors flattens a report's errors into "row field" pairs.
+func rowErrors(report *ImportReport) []string {
+	var got []string
+	for _, row := range report.Errors {
+		for _, fe := range row.Errors {
+			got = append(got, fmt.Sprintf("%d %s", row.Row, fe.Field))
+		}
+	}
+	return got
+}
+
+func countRows(t *testing.T, db *gorm.DB, model interface{}) int64 {
+	t.Helper()
+
+	var n int64
+	if err := db.Model(model).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestImportServiceImportTeams(t *testing.T) {
+	tests := []struct {
+		name       string
+		teams      []string
+		readErrors []ValidationErrors // problems found while reading, by row
+		wantErrors []string
+	}{
+		{"new teams", []string{"Delta", "Epsilon"}, nil, nil},
+		{"name already stored", []string{"Delta", "alpha"}, nil, []string{"2 name"}},
+		{"same name in two rows", []string{"Delta", "DÉLTA"}, nil, []string{"2 name"}},
+		{
+			name:       "read errors are kept",
+			teams:      []string{"Delta", "Epsilon"},
+			readErrors: []ValidationErrors{nil, {{Field: "founded_year", Message: "must be a whole number"}}},
+			wantErrors: []string{"2 founded_year"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, db, _ := newImportService(t)
+			rows := make([]TeamImport, len(tt.teams))
+			for i, name := range tt.teams {
+				rows[i].Team = models.Team{Name: name}
+				if i < len(tt.readErrors) {
+					rows[i].Errors = tt.readErrors[i]
+				}
+			}
+
+			report, err := svc.ImportTeams(rows, false)
+			if got := rowErrors(report); !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("errors = %v, want %v", got, tt.wantErrors)
+			}
+
+			want := int64(4)
+			if tt.wantErrors == nil {
+				if err != nil {
+					t.Fatalf("ImportTeams() error = %v", err)
+				}
+				want += int64(len(tt.teams))
+			} else if !errors.Is(err, ErrInvalidImport) {
+				t.Fatalf("ImportTeams() error = %v, want %v", err, ErrInvalidImport)
+			}
+			if got := countRows(t, db, &models.Team{}); got != want {
+				t.Errorf("%d teams stored, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestImportServiceImportPlayers(t *testing.T) {
+	type row struct {
+		teamID string // name of the stored team whose ID is given, if any
+		team   string
+		number int
+	}
+
+	tests := []struct {
+		name       string
+		rows       []row
+		wantErrors []string
+	}{
+		{"team by name", []row{{team: "alpha", number: 9}, {team: "BETA", number: 9}}, nil},
+		{"team by ID and matching name", []row{{teamID: "Beta", team: "Beta", number: 1}}, nil},
+		{"number already in the squad", []row{{team: "Alpha", number: 10}}, []string{"1 number"}},
+		{"same number in two rows", []row{{team: "Beta", number: 7}, {teamID: "Beta", number: 7}}, []string{"2 number"}},
+		{"team not found", []row{{team: "Omega", number: 1}}, []string{"1 team"}},
+		{"name matches two teams", []row{{team: "gamma", number: 1}}, []string{"1 team"}},
+		{"ID and name disagree", []row{{teamID: "Alpha", team: "Beta", number: 1}}, []string{"1 team"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, db, ids := newImportService(t)
+			rows := make([]PlayerImport, len(tt.rows))
+			for i, r := range tt.rows {
+				rows[i] = PlayerImport{
+					Player: models.Player{TeamID: ids[r.teamID], Name: "Player", Position: models.Defender, Number: r.number},
+					Team:   r.team,
+				}
+			}
+
+			report, err := svc.ImportPlayers(rows, false)
+			if got := rowErrors(report); !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("errors = %v, want %v", got, tt.wantErrors)
+			}
+
+			want := int64(1)
+			if tt.wantErrors == nil {
+				if err != nil {
+					t.Fatalf("ImportPlayers() error = %v", err)
+				}
+				want += int64(len(tt.rows))
+			} else if !errors.Is(err, ErrInvalidImport) {
+				t.Fatalf("ImportPlayers() error = %v, want %v", err, ErrInvalidImport)
+			}
+			if got := countRows(t, db, &models.Player{}); got != want {
+				t.Errorf("%d players stored, want %d", got, want)
+			}
+			if got := countRows(t, db, &models.PlayerContract{}); tt.wantErrors == nil && got != int64(len(tt.rows)) {
+				t.Errorf("%d contracts stored, want %d", got, len(tt.rows))
+			}
+		})
+	}
+}
+
+func TestImportServiceImportMatches(t *testing.T) {
+	missing := uint(99)
+
+	tests := []struct {
+		name       string
+		home, away string
+		seasonID   *uint
+		wantErrors []string
+	}{
+		{"fixture", "Alpha", "Beta", nil, nil},
+		{"same team twice", "Alpha", "alpha", nil, []string{"1 away_team"}},
+		{"season not found", "Alpha", "Beta", &missing, []string{"1 season_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, db, _ := newImportService(t)
+			rows := []MatchImport{{
+				Match:    models.Match{MatchTime: time.Date(2025, 8, 1, 15, 0, 0, 0, time.UTC), SeasonID: tt.seasonID},
+				HomeTeam: tt.home,
+				AwayTeam: tt.away,
+			}}
+
+			report, err := svc.ImportMatches(rows, false)
+			if got := rowErrors(report); !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("errors = %v, want %v", got, tt.wantErrors)
+			}
+			if tt.wantErrors != nil {
+				if !errors.Is(err, ErrInvalidImport) {
+					t.Fatalf("ImportMatches() error = %v, want %v", err, ErrInvalidImport)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ImportMatches() error = %v", err)
+			}
+
+			var match models.Match
+			if err := db.First(&match, report.IDs[0]).Error; err != nil {
+				t.Fatal(err)
+			}
+			if match.Status != models.Scheduled {
+				t.Errorf("status = %s, want %s", match.Status, models.Scheduled)
+			}
+		})
+	}
+}
+
+func TestImportServiceDryRun(t *testing.T) {
+	svc, db, _ := newImportService(t)
+
+	report, err := svc.ImportPlayers([]PlayerImport{{
+		Player: models.Player{Name: "Player", Position: models.Defender, Number: 4},
+		Team:   "Beta",
+	}}, true)
+	if err != nil {
+		t.Fatalf("ImportPlayers() error = %v", err)
+	}
+	if !report.DryRun || report.Created != 0 || len(report.IDs) != 0 {
+		t.Errorf("report = %+v, want a dry run that created nothing", report)
+	}
+	if got := countRows(t, db, &models.Player{}); got != 1 {
+		t.Errorf("%d players stored, want only the stored one", got)
+	}
+}
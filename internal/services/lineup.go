@@ -0,0 +1,114 @@
+package services
+
+import (
+	"sort"
+
+	"xyz-football/internal/models"
+)
+
+// appearance is the stretch of a match a player spent on the pitch, from and
+// to an eventTime.
+type appearance struct {
+	on, off int
+}
+
+func (a appearance) covers(at int) bool {
+	return a.on <= at && at <= a.off
+}
+
+// minutes counts the minutes played; stoppage time is not counted.
+func (a appearance) minutes() int {
+	return a.off/100 - a.on/100
+}
+
+// matchLength is the last minute of a match, 120 when it went to extra time.
+func matchLength(match models.Match) int {
+	if match.HomeExtraTimeScore != nil && match.AwayExtraTimeScore != nil {
+		return 120
+	}
+	return 90
+}
+
+// appearances works out when each player of a lineup was on the pitch from
+// the substitutions and sendings off, for a match that lasted until the
+// given minute. Substitutes who never came on are left out.
+func appearances(lineup []models.LineupEntry, subs []models.Substitution, cards []models.Card, length int) map[uint]appearance {
+	final := eventTime(length, 99) // whoever is still on plays through stoppage time
+
+	result := make(map[uint]appearance)
+	for _, entry := range lineup {
+		if entry.Role == models.Starter {
+			result[entry.PlayerID] = appearance{on: 0, off: final}
+		}
+	}
+
+	sorted := make([]models.Substitution, len(subs))
+	copy(sorted, subs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return eventTime(sorted[i].Minute, sorted[i].AddedTime) < eventTime(sorted[j].Minute, sorted[j].AddedTime)
+	})
+	for _, sub := range sorted {
+		at := eventTime(sub.Minute, sub.AddedTime)
+		if a, ok := result[sub.PlayerOffID]; ok && at < a.off {
+			a.off = at
+			result[sub.PlayerOffID] = a
+		}
+		result[sub.PlayerOnID] = appearance{on: at, off: final}
+	}
+
+	for _, card := range cards {
+		if !card.Type.IsDismissal() {
+			continue
+		}
+		at := eventTime(card.Minute, card.AddedTime)
+		if a, ok := result[card.PlayerID]; ok && at < a.off {
+			a.off = at
+			result[card.PlayerID] = a
+		}
+	}
+
+	return result
+}
+
+// matchAppearances works out who was on the pitch in each finished match,
+// keyed by match and then player. matches need their cards loaded; matches
+// without a lineup are skipped.
+func matchAppearances(matches []models.Match, lineups []models.LineupEntry, subs []models.Substitution) map[uint]map[uint]appearance {
+	lineupsByMatch := make(map[uint][]models.LineupEntry)
+	for _, entry := range lineups {
+		lineupsByMatch[entry.MatchID] = append(lineupsByMatch[entry.MatchID], entry)
+	}
+	subsByMatch := make(map[uint][]models.Substitution)
+	for _, sub := range subs {
+		subsByMatch[sub.MatchID] = append(subsByMatch[sub.MatchID], sub)
+	}
+
+	result := make(map[uint]map[uint]appearance)
+	for _, match := range matches {
+		if match.Status != models.Finished || len(lineupsByMatch[match.ID]) == 0 {
+			continue
+		}
+		result[match.ID] = appearances(lineupsByMatch[match.ID], subsByMatch[match.ID], match.Cards, matchLength(match))
+	}
+	return result
+}
+
+// minutesPlayed adds up the minutes every player spent on the pitch in the
+// finished matches, keyed by player and team so that a player who moved
+// clubs has their minutes split the same way as the leaderboards. matches
+// need their cards loaded; matches without a lineup are skipped.
+func minutesPlayed(matches []models.Match, lineups []models.LineupEntry, subs []models.Substitution) map[[2]uint]int {
+	teams := make(map[[2]uint]uint) // team of each player, keyed by match and player
+	for _, entry := range lineups {
+		teams[[2]uint{entry.MatchID, entry.PlayerID}] = entry.TeamID
+	}
+
+	minutes := make(map[[2]uint]int)
+	for matchID, players := range matchAppearances(matches, lineups, subs) {
+		for playerID, a := range players {
+			teamID := teams[[2]uint{matchID, playerID}]
+			minutes[[2]uint{playerID, teamID}] += a.minutes()
+		}
+	}
+	return minutes
+}
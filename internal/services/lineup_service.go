@@ -0,0 +1,352 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
+)
+
+const (
+	startingLineupSize = 11
+	maxBenchSize       = 12
+	maxSubstitutions   = 5
+)
+
+// ErrSubstitutionInUse means a later substitution relies on the one being
+// deleted.
+var ErrSubstitutionInUse = errors.New("a later substitution involves the same players, delete that one first")
+
+type LineupService interface {
+	SubmitLineup(matchID uint, lineup TeamLineup) (*MatchLineups, error)
+	GetLineups(matchID uint) (*MatchLineups, error)
+	AddSubstitution(matchID uint, sub *models.Substitution) error
+	DeleteSubstitution(matchID, substitutionID uint) error
+}
+
+type lineupService struct {
+	repo         repositories.LineupRepository
+	matchRepo    repositories.MatchRepository
+	contractRepo repositories.ContractRepository
+	cardRepo     repositories.CardRepository
+}
+
+// TeamLineup is the matchday squad of one team. Shirt number and position
+// default to the ones the player is registered with.
+type TeamLineup struct {
+	TeamID   uint
+	Starters []models.LineupEntry
+	Bench    []models.LineupEntry
+}
+
+type MatchLineups struct {
+	MatchID uint      `json:"match_id"`
+	Home    TeamSheet `json:"home"`
+	Away    TeamSheet `json:"away"`
+}
+
+type TeamSheet struct {
+	TeamID        uint                  `json:"team_id"`
+	TeamName      string                `json:"team_name"`
+	Starters      []LineupPlayer        `json:"starters"`
+	Bench         []LineupPlayer        `json:"bench"`
+	Substitutions []models.Substitution `json:"substitutions"`
+}
+
+// LineupPlayer is a squad member; minutes played are only known once the match is finished.
+type LineupPlayer struct {
+	PlayerID      uint   `json:"player_id"`
+	PlayerName    string `json:"player_name"`
+	ShirtNumber   int    `json:"shirt_number"`
+	Position      string `json:"position"`
+	MinutesPlayed int    `json:"minutes_played"`
+}
+
+func NewLineupService(
+	repo repositories.LineupRepository,
+	matchRepo repositories.MatchRepository,
+	contractRepo repositories.ContractRepository,
+	cardRepo repositories.CardRepository,
+) LineupService {
+	return &lineupService{
+		repo:         repo,
+		matchRepo:    matchRepo,
+		contractRepo: contractRepo,
+		cardRepo:     cardRepo,
+	}
+}
+
+func (s *lineupService) SubmitLineup(matchID uint, lineup TeamLineup) (*MatchLineups, error) {
+	match, err := s.matchRepo.FindByID(matchID)
+	if err != nil {
+		return nil, errors.New("match not found")
+	}
+	if match.Status == models.Cancelled {
+		return nil, errors.New("cannot submit a lineup for a cancelled match")
+	}
+	if lineup.TeamID != match.HomeTeamID && lineup.TeamID != match.AwayTeamID {
+		return nil, errors.New("team does not play in this match")
+	}
+
+	subs, err := s.repo.FindSubstitutions(matchID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		if sub.TeamID == lineup.TeamID {
+			return nil, errors.New("the lineup cannot change once substitutions are recorded, remove them first")
+		}
+	}
+
+	// Only players registered with the team on the match date can be named
+	squad, err := s.contractRepo.FindSquadAt(lineup.TeamID, match.MatchTime)
+	if err != nil {
+		return nil, err
+	}
+	squadByID := make(map[uint]models.Player, len(squad))
+	for _, p := range squad {
+		squadByID[p.ID] = p
+	}
+
+	suspended, err := suspendedPlayers(s.matchRepo, s.cardRepo, match)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs ValidationErrors
+	if len(lineup.Starters) != startingLineupSize {
+		errs.add("starters", "a starting lineup needs exactly %d players, got %d", startingLineupSize, len(lineup.Starters))
+	}
+	if len(lineup.Bench) > maxBenchSize {
+		errs.add("bench", "at most %d substitutes can be named, got %d", maxBenchSize, len(lineup.Bench))
+	}
+
+	entries := make([]models.LineupEntry, 0, len(lineup.Starters)+len(lineup.Bench))
+	listed := make(map[uint]string)
+	shirts := make(map[int]string)
+	goalkeepers := 0
+
+	add := func(field string, entry models.LineupEntry, role models.LineupRole) {
+		player, ok := squadByID[entry.PlayerID]
+		if !ok {
+			errs.add(field+".player_id", "player %d is not registered with the team", entry.PlayerID)
+			return
+		}
+		if prev, dup := listed[entry.PlayerID]; dup {
+			errs.add(field+".player_id", "%s is already listed as %s", player.Name, prev)
+			return
+		}
+		listed[entry.PlayerID] = field
+		if reason, banned := suspended[entry.PlayerID]; banned {
+			errs.add(field+".player_id", "%s is suspended for this match (%s)", player.Name, reason)
+		}
+
+		if entry.ShirtNumber == 0 {
+			entry.ShirtNumber = player.Number
+		}
+		if other, taken := shirts[entry.ShirtNumber]; taken {
+			errs.add(field+".shirt_number", "shirt number %d is already worn by %s", entry.ShirtNumber, other)
+		}
+		shirts[entry.ShirtNumber] = player.Name
+
+		if entry.Position == "" {
+			entry.Position = player.Position
+		}
+		if role == models.Starter && entry.Position == models.Goalkeeper {
+			goalkeepers++
+		}
+
+		entries = append(entries, models.LineupEntry{
+			MatchID:     matchID,
+			TeamID:      lineup.TeamID,
+			PlayerID:    entry.PlayerID,
+			Role:        role,
+			ShirtNumber: entry.ShirtNumber,
+			Position:    entry.Position,
+		})
+	}
+
+	for i, entry := range lineup.Starters {
+		add(fmt.Sprintf("starters[%d]", i), entry, models.Starter)
+	}
+	for i, entry := range lineup.Bench {
+		add(fmt.Sprintf("bench[%d]", i), entry, models.Substitute)
+	}
+	if goalkeepers != 1 {
+		errs.add("starters", "the starting lineup needs exactly one goalkeeper, got %d", goalkeepers)
+	}
+
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReplaceLineup(matchID, lineup.TeamID, entries); err != nil {
+		return nil, err
+	}
+
+	return s.GetLineups(matchID)
+}
+
+func (s *lineupService) GetLineups(matchID uint) (*MatchLineups, error) {
+	match, err := s.matchRepo.FindByID(matchID)
+	if err != nil {
+		return nil, errors.New("match not found")
+	}
+
+	entries, err := s.repo.FindByMatch(matchID)
+	if err != nil {
+		return nil, err
+	}
+	subs, err := s.repo.FindSubstitutions(matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	var minutes map[uint]appearance
+	if match.Status == models.Finished {
+		minutes = appearances(entries, subs, match.Cards, matchLength(*match))
+	}
+
+	lineups := &MatchLineups{
+		MatchID: match.ID,
+		Home:    newTeamSheet(match.HomeTeamID, match.HomeTeam.Name),
+		Away:    newTeamSheet(match.AwayTeamID, match.AwayTeam.Name),
+	}
+	sheets := map[uint]*TeamSheet{
+		match.HomeTeamID: &lineups.Home,
+		match.AwayTeamID: &lineups.Away,
+	}
+
+	for _, entry := range entries {
+		sheet, ok := sheets[entry.TeamID]
+		if !ok {
+			continue
+		}
+		player := LineupPlayer{
+			PlayerID:      entry.PlayerID,
+			PlayerName:    entry.Player.Name,
+			ShirtNumber:   entry.ShirtNumber,
+			Position:      string(entry.Position),
+			MinutesPlayed: minutes[entry.PlayerID].minutes(),
+		}
+		if entry.Role == models.Starter {
+			sheet.Starters = append(sheet.Starters, player)
+		} else {
+			sheet.Bench = append(sheet.Bench, player)
+		}
+	}
+	for _, sub := range subs {
+		if sheet, ok := sheets[sub.TeamID]; ok {
+			sheet.Substitutions = append(sheet.Substitutions, sub)
+		}
+	}
+
+	return lineups, nil
+}
+
+func newTeamSheet(teamID uint, name string) TeamSheet {
+	return TeamSheet{
+		TeamID:        teamID,
+		TeamName:      name,
+		Starters:      []LineupPlayer{},
+		Bench:         []LineupPlayer{},
+		Substitutions: []models.Substitution{},
+	}
+}
+
+func (s *lineupService) AddSubstitution(matchID uint, sub *models.Substitution) error {
+	match, err := s.matchRepo.FindByID(matchID)
+	if err != nil {
+		return errors.New("match not found")
+	}
+	if match.Status == models.Cancelled {
+		return errors.New("cannot record substitutions for a cancelled match")
+	}
+	if sub.TeamID != match.HomeTeamID && sub.TeamID != match.AwayTeamID {
+		return errors.New("team does not play in this match")
+	}
+	if sub.PlayerOffID == sub.PlayerOnID {
+		return errors.New("a player cannot replace themselves")
+	}
+
+	var errs ValidationErrors
+	validateMinute("substitution", sub.Minute, sub.AddedTime, match.TieID != nil, &errs)
+	if err := errs.err(); err != nil {
+		return err
+	}
+
+	entries, err := s.repo.FindByMatch(matchID)
+	if err != nil {
+		return err
+	}
+	var lineup []models.LineupEntry
+	onBench := make(map[uint]bool)
+	for _, entry := range entries {
+		if entry.TeamID != sub.TeamID {
+			continue
+		}
+		lineup = append(lineup, entry)
+		if entry.Role == models.Substitute {
+			onBench[entry.PlayerID] = true
+		}
+	}
+	if len(lineup) == 0 {
+		return errors.New("submit the team's lineup before recording substitutions")
+	}
+
+	existing, err := s.repo.FindSubstitutions(matchID)
+	if err != nil {
+		return err
+	}
+	var teamSubs []models.Substitution
+	for _, other := range existing {
+		if other.TeamID != sub.TeamID {
+			continue
+		}
+		if other.PlayerOffID == sub.PlayerOffID {
+			return errors.New("the player has already been substituted")
+		}
+		teamSubs = append(teamSubs, other)
+	}
+	if len(teamSubs) >= maxSubstitutions {
+		return fmt.Errorf("a team can make at most %d substitutions", maxSubstitutions)
+	}
+
+	onPitch := appearances(lineup, teamSubs, match.Cards, 120)
+	if a, ok := onPitch[sub.PlayerOffID]; !ok || !a.covers(eventTime(sub.Minute, sub.AddedTime)) {
+		return errors.New("the player coming off is not on the pitch at that minute")
+	}
+	if _, played := onPitch[sub.PlayerOnID]; played || !onBench[sub.PlayerOnID] {
+		return errors.New("the player coming on must be an unused substitute")
+	}
+
+	sub.MatchID = matchID
+	return s.repo.CreateSubstitution(sub)
+}
+
+// DeleteSubstitution only deletes the latest substitution of the players it
+// involves: a later one taking the substitute off again relies on them having
+// come on, so that one has to go first.
+func (s *lineupService) DeleteSubstitution(matchID, substitutionID uint) error {
+	subs, err := s.repo.FindSubstitutions(matchID)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		if sub.ID != substitutionID {
+			continue
+		}
+		at := eventTime(sub.Minute, sub.AddedTime)
+		for _, other := range subs {
+			if other.ID == sub.ID || other.TeamID != sub.TeamID || eventTime(other.Minute, other.AddedTime) < at {
+				continue
+			}
+			if other.PlayerOffID == sub.PlayerOnID || other.PlayerOnID == sub.PlayerOffID {
+				return ErrSubstitutionInUse
+			}
+		}
+		return s.repo.DeleteSubstitution(substitutionID)
+	}
+	return errors.New("substitution not found")
+}
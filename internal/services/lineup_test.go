@@ -0,0 +1,93 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"xyz-football/internal/models"
+)
+
+func TestMinutesPlayed(t *testing.T) {
+	played := func(id uint) models.Match {
+		return finishedMatch(id, 1, 2, 1, 0)
+	}
+	starter := func(matchID, teamID, playerID uint) models.LineupEntry {
+		return models.LineupEntry{MatchID: matchID, TeamID: teamID, PlayerID: playerID, Role: models.Starter}
+	}
+	bench := func(matchID, teamID, playerID uint) models.LineupEntry {
+		return models.LineupEntry{MatchID: matchID, TeamID: teamID, PlayerID: playerID, Role: models.Substitute}
+	}
+
+	extraTime := played(1)
+	extraTime.HomeExtraTimeScore, extraTime.AwayExtraTimeScore = intPtr(1), intPtr(0)
+
+	sentOff := played(1)
+	sentOff.Cards = []models.Card{{MatchID: 1, PlayerID: 10, TeamID: 1, Type: models.RedCard, Minute: 30, AddedTime: 2}}
+
+	scheduled := played(1)
+	scheduled.Status = models.Scheduled
+
+	tests := []struct {
+		name    string
+		matches []models.Match
+		lineups []models.LineupEntry
+		subs    []models.Substitution
+		want    map[[2]uint]int
+	}{
+		{
+			name:    "starter plays the whole match",
+			matches: []models.Match{played(1)},
+			lineups: []models.LineupEntry{starter(1, 1, 10)},
+			want:    map[[2]uint]int{{10, 1}: 90},
+		},
+		{
+			name:    "extra time",
+			matches: []models.Match{extraTime},
+			lineups: []models.LineupEntry{starter(1, 1, 10)},
+			want:    map[[2]uint]int{{10, 1}: 120},
+		},
+		{
+			name:    "substitution splits the minutes",
+			matches: []models.Match{played(1)},
+			lineups: []models.LineupEntry{starter(1, 1, 10), bench(1, 1, 11), bench(1, 1, 12)},
+			subs:    []models.Substitution{{MatchID: 1, TeamID: 1, PlayerOffID: 10, PlayerOnID: 11, Minute: 60}},
+			want:    map[[2]uint]int{{10, 1}: 60, {11, 1}: 30},
+		},
+		{
+			name:    "substitute taken off again",
+			matches: []models.Match{played(1)},
+			lineups: []models.LineupEntry{starter(1, 1, 10), bench(1, 1, 11), bench(1, 1, 12)},
+			subs: []models.Substitution{
+				{MatchID: 1, TeamID: 1, PlayerOffID: 11, PlayerOnID: 12, Minute: 80},
+				{MatchID: 1, TeamID: 1, PlayerOffID: 10, PlayerOnID: 11, Minute: 45, AddedTime: 1},
+			},
+			want: map[[2]uint]int{{10, 1}: 45, {11, 1}: 35, {12, 1}: 10},
+		},
+		{
+			name:    "sending off ends the appearance, stoppage time not counted",
+			matches: []models.Match{sentOff},
+			lineups: []models.LineupEntry{starter(1, 1, 10)},
+			want:    map[[2]uint]int{{10, 1}: 30},
+		},
+		{
+			name:    "unfinished matches and matches without a lineup are skipped",
+			matches: []models.Match{scheduled, played(2)},
+			lineups: []models.LineupEntry{starter(1, 1, 10)},
+			want:    map[[2]uint]int{},
+		},
+		{
+			name:    "minutes are split by team after a move",
+			matches: []models.Match{played(1), played(2), finishedMatch(3, 3, 2, 0, 0)},
+			lineups: []models.LineupEntry{starter(1, 1, 10), starter(2, 1, 10), starter(3, 3, 10)},
+			want:    map[[2]uint]int{{10, 1}: 180, {10, 3}: 90},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := minutesPlayed(tt.matches, tt.lineups, tt.subs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("minutesPlayed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
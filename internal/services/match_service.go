@@ -2,6 +2,8 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"xyz-football/internal/models"
@@ -10,24 +12,90 @@ import (
 
 type MatchService interface {
 	CreateMatch(match *models.Match) error
-	GetAllMatches() ([]models.Match, error)
+	ListMatches(filter repositories.MatchListFilter, opts repositories.ListOptions) ([]models.Match, int64, error)
 	GetMatchByID(id uint) (*models.Match, error)
-	GetMatchesByDateRange(start, end time.Time) ([]models.Match, error)
 	GetMatchesByTeam(teamID uint) ([]models.Match, error)
 	UpdateMatch(match *models.Match) error
 	DeleteMatch(id uint) error
-	ReportMatchResult(matchID uint, homeScore, awayScore int, goals []models.Goal) error
+	ReportMatchResult(matchID uint, result MatchResult) error
+	ChangeStatus(matchID uint, change StatusChange) (*models.Match, error)
+	GetMatchHistory(matchID uint) ([]models.MatchTransition, error)
+	GenerateFixtures(seasonID uint, opts FixtureOptions) ([]models.Match, error)
+}
+
+// StatusChange moves a match to another state of its lifecycle. MatchTime is
+// only used when rescheduling; WinnerTeamID and the scores only when awarding.
+// From, when set, narrows the states the match may leave for endpoints that
+// share a target state: kick-off and resume both go live.
+type StatusChange struct {
+	Status       models.MatchStatus
+	From         []models.MatchStatus
+	AdminID      uint
+	Reason       string
+	MatchTime    *time.Time
+	WinnerTeamID *uint
+	HomeScore    *int
+	AwayScore    *int
+}
+
+// MatchResult is the outcome reported for a match. Extra-time goals are
+// already included in HomeScore/AwayScore; the extra-time and penalty fields
+// are only used for knockout ties.
+type MatchResult struct {
+	HomeScore          int
+	AwayScore          int
+	HomeExtraTimeScore *int
+	AwayExtraTimeScore *int
+	HomePenalties      *int
+	AwayPenalties      *int
+	Goals              []models.Goal
+	Cards              []models.Card
+	ReportedBy         uint // admin recorded in the match history
+}
+
+// FixtureOptions describes a round-robin schedule to generate for a season.
+type FixtureOptions struct {
+	TeamIDs          []uint
+	StartDate        time.Time
+	IntervalDays     int // days between matchdays, defaults to 7
+	DoubleRoundRobin bool
+	CompetitionID    *uint
+	DryRun           bool // return the proposed fixtures without saving them
 }
 
 type matchService struct {
-	repo     repositories.MatchRepository
-	goalRepo repositories.GoalRepository
+	repo            repositories.MatchRepository
+	goalRepo        repositories.GoalRepository
+	playerRepo      repositories.PlayerRepository
+	cardRepo        repositories.CardRepository
+	lineupRepo      repositories.LineupRepository
+	contractRepo    repositories.ContractRepository
+	teamRepo        repositories.TeamRepository
+	seasonRepo      repositories.SeasonRepository
+	competitionRepo repositories.CompetitionRepository
 }
 
-func NewMatchService(matchRepo repositories.MatchRepository, goalRepo repositories.GoalRepository) MatchService {
+func NewMatchService(
+	matchRepo repositories.MatchRepository,
+	goalRepo repositories.GoalRepository,
+	playerRepo repositories.PlayerRepository,
+	cardRepo repositories.CardRepository,
+	lineupRepo repositories.LineupRepository,
+	contractRepo repositories.ContractRepository,
+	teamRepo repositories.TeamRepository,
+	seasonRepo repositories.SeasonRepository,
+	competitionRepo repositories.CompetitionRepository,
+) MatchService {
 	return &matchService{
-		repo:     matchRepo,
-		goalRepo: goalRepo,
+		repo:            matchRepo,
+		goalRepo:        goalRepo,
+		playerRepo:      playerRepo,
+		cardRepo:        cardRepo,
+		lineupRepo:      lineupRepo,
+		contractRepo:    contractRepo,
+		teamRepo:        teamRepo,
+		seasonRepo:      seasonRepo,
+		competitionRepo: competitionRepo,
 	}
 }
 
@@ -37,6 +105,10 @@ func (s *matchService) CreateMatch(match *models.Match) error {
 		return errors.New("home and away teams must be different")
 	}
 
+	if err := s.validateScope(match); err != nil {
+		return err
+	}
+
 	// Set default status if not provided
 	if match.Status == "" {
 		match.Status = models.Scheduled
@@ -45,18 +117,14 @@ func (s *matchService) CreateMatch(match *models.Match) error {
 	return s.repo.Create(match)
 }
 
-func (s *matchService) GetAllMatches() ([]models.Match, error) {
-	return s.repo.FindAll()
+func (s *matchService) ListMatches(filter repositories.MatchListFilter, opts repositories.ListOptions) ([]models.Match, int64, error) {
+	return s.repo.List(filter, opts)
 }
 
 func (s *matchService) GetMatchByID(id uint) (*models.Match, error) {
 	return s.repo.FindByID(id)
 }
 
-func (s *matchService) GetMatchesByDateRange(start, end time.Time) ([]models.Match, error) {
-	return s.repo.FindByDateRange(start, end)
-}
-
 func (s *matchService) GetMatchesByTeam(teamID uint) ([]models.Match, error) {
 	return s.repo.FindByTeamID(teamID)
 }
@@ -68,29 +136,120 @@ func (s *matchService) UpdateMatch(match *models.Match) error {
 		return errors.New("match not found")
 	}
 
-	// Prevent updating finished matches
-	if existingMatch.Status == models.Finished {
-		return errors.New("cannot update a finished match")
+	// Only fixtures that have not kicked off can be edited
+	if !existingMatch.Status.IsEditable() {
+		return fmt.Errorf("cannot update a %s match", existingMatch.Status)
+	}
+
+	if err := s.validateScope(match); err != nil {
+		return err
+	}
+
+	// Status and result are only changed through their own transitions
+	existingMatch.MatchTime = match.MatchTime
+	existingMatch.HomeTeamID = match.HomeTeamID
+	existingMatch.AwayTeamID = match.AwayTeamID
+	existingMatch.SeasonID = match.SeasonID
+	existingMatch.CompetitionID = match.CompetitionID
+
+	if err := s.repo.Update(existingMatch); err != nil {
+		return err
+	}
+
+	*match = *existingMatch
+	return nil
+}
+
+// validateScope makes sure the season and competition a match is assigned to exist.
+func (s *matchService) validateScope(match *models.Match) error {
+	return newScopeValidator(s.seasonRepo, s.competitionRepo).validate(match).err()
+}
+
+// scopeValidator checks the season and competition matches are assigned to.
+// It remembers what it looked up, so a batch of fixtures queries each once.
+type scopeValidator struct {
+	seasonRepo      repositories.SeasonRepository
+	competitionRepo repositories.CompetitionRepository
+	seasons         map[uint]bool
+	competitions    map[uint]bool
+}
+
+func newScopeValidator(seasonRepo repositories.SeasonRepository, competitionRepo repositories.CompetitionRepository) *scopeValidator {
+	return &scopeValidator{
+		seasonRepo:      seasonRepo,
+		competitionRepo: competitionRepo,
+		seasons:         make(map[uint]bool),
+		competitions:    make(map[uint]bool),
 	}
+}
 
-	return s.repo.Update(match)
+func (v *scopeValidator) validate(match *models.Match) ValidationErrors {
+	var errs ValidationErrors
+
+	if match.SeasonID != nil {
+		found, ok := v.seasons[*match.SeasonID]
+		if !ok {
+			_, err := v.seasonRepo.FindByID(*match.SeasonID)
+			found = err == nil
+			v.seasons[*match.SeasonID] = found
+		}
+		if !found {
+			errs.add("season_id", "season not found")
+		}
+	}
+
+	if match.CompetitionID != nil {
+		found, ok := v.competitions[*match.CompetitionID]
+		if !ok {
+			_, err := v.competitionRepo.FindByID(*match.CompetitionID)
+			found = err == nil
+			v.competitions[*match.CompetitionID] = found
+		}
+		if !found {
+			errs.add("competition_id", "competition not found")
+		}
+	}
+
+	return errs
 }
 
 func (s *matchService) DeleteMatch(id uint) error {
 	return s.repo.Delete(id)
 }
 
-func (s *matchService) ReportMatchResult(matchID uint, homeScore, awayScore int, goals []models.Goal) error {
+func (s *matchService) ReportMatchResult(matchID uint, result MatchResult) error {
 	match, err := s.repo.FindByID(matchID)
 	if err != nil {
 		return errors.New("match not found")
 	}
 
+	if !match.Status.CanTransitionTo(models.Finished) {
+		return fmt.Errorf("cannot report a result for a %s match", match.Status)
+	}
+
+	if err := s.validateResult(match, &result); err != nil {
+		return err
+	}
+
+	transition := &models.MatchTransition{
+		MatchID:    match.ID,
+		FromStatus: match.Status,
+		ToStatus:   models.Finished,
+		AdminID:    result.ReportedBy,
+	}
+	if match.Status == models.Finished {
+		transition.Reason = "result corrected"
+	}
+
 	// Update match scores and status
-	homeScoreInt := homeScore
-	awayScoreInt := awayScore
-	match.HomeScore = &homeScoreInt
-	match.AwayScore = &awayScoreInt
+	homeScore := result.HomeScore
+	awayScore := result.AwayScore
+	match.HomeScore = &homeScore
+	match.AwayScore = &awayScore
+	match.HomeExtraTimeScore = result.HomeExtraTimeScore
+	match.AwayExtraTimeScore = result.AwayExtraTimeScore
+	match.HomePenalties = result.HomePenalties
+	match.AwayPenalties = result.AwayPenalties
 	match.Status = models.Finished
 
 	// Use repository's transaction support
@@ -100,6 +259,10 @@ func (s *matchService) ReportMatchResult(matchID uint, homeScore, awayScore int,
 			return err
 		}
 
+		if err := repo.CreateTransition(transition); err != nil {
+			return err
+		}
+
 		// Delete existing goals
 		db := repo.GetDB()
 		if err := db.Where("match_id = ?", matchID).Delete(&models.Goal{}).Error; err != nil {
@@ -107,13 +270,642 @@ func (s *matchService) ReportMatchResult(matchID uint, homeScore, awayScore int,
 		}
 
 		// Add new goals
-		for _, goal := range goals {
+		for _, goal := range result.Goals {
 			goal.MatchID = matchID
 			if err := db.Create(&goal).Error; err != nil {
 				return err
 			}
 		}
 
+		// Replace the bookings the same way
+		if err := db.Where("match_id = ?", matchID).Delete(&models.Card{}).Error; err != nil {
+			return err
+		}
+		for _, card := range result.Cards {
+			card.MatchID = matchID
+			if err := db.Create(&card).Error; err != nil {
+				return err
+			}
+		}
+
+		// Move the winner of a completed knockout tie into the next round
+		if match.TieID != nil {
+			return advanceTie(repo, *match.TieID)
+		}
+
+		return nil
+	})
+}
+
+func (s *matchService) ChangeStatus(matchID uint, change StatusChange) (*models.Match, error) {
+	match, err := s.repo.FindByID(matchID)
+	if err != nil {
+		return nil, errors.New("match not found")
+	}
+
+	if change.Status == models.Finished {
+		return nil, errors.New("use the match report to finish a match")
+	}
+	if !match.Status.CanTransitionTo(change.Status) || (len(change.From) > 0 && !slices.Contains(change.From, match.Status)) {
+		return nil, fmt.Errorf("cannot move a %s match to %s", match.Status, change.Status)
+	}
+
+	transition := &models.MatchTransition{
+		MatchID:    match.ID,
+		FromStatus: match.Status,
+		ToStatus:   change.Status,
+		AdminID:    change.AdminID,
+		Reason:     change.Reason,
+	}
+
+	voidGoals := false
+	switch change.Status {
+	case models.Scheduled:
+		if change.MatchTime == nil {
+			return nil, errors.New("a new match time is required to reschedule")
+		}
+		match.MatchTime = *change.MatchTime
+
+	case models.Awarded:
+		if err := applyAwardedScore(match, change); err != nil {
+			return nil, err
+		}
+		// An awarded result replaces whatever happened on the pitch
+		voidGoals = true
+
+	case models.Cancelled, models.Abandoned:
+		match.HomeScore, match.AwayScore = nil, nil
+	}
+	match.Status = change.Status
+
+	err = s.repo.WithTransaction(func(repo repositories.MatchRepository) error {
+		if err := repo.Update(match); err != nil {
+			return err
+		}
+
+		if err := repo.CreateTransition(transition); err != nil {
+			return err
+		}
+
+		if voidGoals {
+			if err := repo.GetDB().Where("match_id = ?", match.ID).Delete(&models.Goal{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if match.TieID != nil && match.Status.HasFinalResult() {
+			return advanceTie(repo, *match.TieID)
+		}
+
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return match, nil
+}
+
+// applyAwardedScore sets the score of an awarded match: 3-0 to the winner
+// unless both scores are given explicitly.
+func applyAwardedScore(match *models.Match, change StatusChange) error {
+	if change.WinnerTeamID == nil {
+		return errors.New("the team the match is awarded to is required")
+	}
+
+	winner := *change.WinnerTeamID
+	if winner != match.HomeTeamID && winner != match.AwayTeamID {
+		return errors.New("the match can only be awarded to one of its teams")
+	}
+
+	if (change.HomeScore == nil) != (change.AwayScore == nil) {
+		return errors.New("give both home_score and away_score for an awarded score, or neither for 3-0")
+	}
+
+	homeScore, awayScore := 3, 0
+	if winner == match.AwayTeamID {
+		homeScore, awayScore = 0, 3
+	}
+	if change.HomeScore != nil {
+		homeScore, awayScore = *change.HomeScore, *change.AwayScore
+	}
+
+	if (winner == match.HomeTeamID && homeScore <= awayScore) ||
+		(winner == match.AwayTeamID && awayScore <= homeScore) {
+		return errors.New("the awarded score must favour the winning team")
+	}
+
+	match.HomeScore = &homeScore
+	match.AwayScore = &awayScore
+	match.HomeExtraTimeScore, match.AwayExtraTimeScore = nil, nil
+	match.HomePenalties, match.AwayPenalties = nil, nil
+	return nil
+}
+
+func (s *matchService) GetMatchHistory(matchID uint) ([]models.MatchTransition, error) {
+	if _, err := s.repo.FindByID(matchID); err != nil {
+		return nil, errors.New("match not found")
+	}
+
+	return s.repo.FindTransitions(matchID)
+}
+
+// validateResult checks a reported result as a whole and returns every
+// problem it finds as ValidationErrors rather than stopping at the first.
+// Goals are completed with their type and the team they are credited to,
+// cards with the team of the booked player.
+func (s *matchService) validateResult(match *models.Match, result *MatchResult) error {
+	var errs ValidationErrors
+	validateKnockoutResult(match, *result, &errs)
+
+	ids := make([]uint, 0, len(result.Goals)+len(result.Cards))
+	for _, goal := range result.Goals {
+		ids = append(ids, goal.PlayerID)
+		if goal.AssistPlayerID != nil {
+			ids = append(ids, *goal.AssistPlayerID)
+		}
+		if goal.SecondAssistPlayerID != nil {
+			ids = append(ids, *goal.SecondAssistPlayerID)
+		}
+	}
+	for _, card := range result.Cards {
+		ids = append(ids, card.PlayerID)
+	}
+	players, err := s.playerRepo.FindByIDs(ids)
+	if err != nil {
+		return err
+	}
+	// Players count for the team they were registered with on the match date
+	teams, err := s.contractRepo.FindTeamsAt(ids, match.MatchTime)
+	if err != nil {
+		return err
+	}
+	playersByID := make(map[uint]models.Player, len(players))
+	for _, p := range players {
+		p.TeamID = teams[p.ID]
+		playersByID[p.ID] = p
+	}
+
+	extraTime := result.HomeExtraTimeScore != nil && result.AwayExtraTimeScore != nil
+	dismissals := validateCards(match, result, playersByID, extraTime, &errs)
+
+	// With a lineup on record, scorers and assisters must have been on the pitch
+	onPitch, lineupTeams, err := s.pitchTimes(match, *result, extraTime)
+	if err != nil {
+		return err
+	}
+	checkOnPitch := func(field string, player models.Player, at int) {
+		if !lineupTeams[player.TeamID] {
+			return
+		}
+		if a, ok := onPitch[player.ID]; !ok || !a.covers(at) {
+			errs.add(field, "%s was not on the pitch at %s", player.Name, formatMinute(at/100, at%100))
+		}
+	}
+
+	var homeGoals, awayGoals, homeExtraTimeGoals, awayExtraTimeGoals int
+	seen := make(map[[3]int]int, len(result.Goals))
+
+	for i, goal := range result.Goals {
+		field := fmt.Sprintf("goals[%d]", i)
+		if goal.Type == "" {
+			result.Goals[i].Type = models.OpenPlay
+		}
+
+		validateMinute(field, goal.Minute, goal.AddedTime, extraTime, &errs)
+		if i > 0 {
+			prev := result.Goals[i-1]
+			if eventTime(goal.Minute, goal.AddedTime) < eventTime(prev.Minute, prev.AddedTime) {
+				errs.add(field+".minute", "goals must be listed in order, %s comes before %s",
+					formatMinute(goal.Minute, goal.AddedTime), formatMinute(prev.Minute, prev.AddedTime))
+			}
+		}
+
+		key := [3]int{int(goal.PlayerID), goal.Minute, goal.AddedTime}
+		if j, ok := seen[key]; ok {
+			errs.add(field, "same player and minute as goals[%d]", j)
+		}
+		seen[key] = i
+
+		player, ok := playersByID[goal.PlayerID]
+		if !ok {
+			errs.add(field+".player_id", "player %d not found", goal.PlayerID)
+			continue
+		}
+		at := eventTime(goal.Minute, goal.AddedTime)
+		if lineupTeams[player.TeamID] {
+			checkOnPitch(field+".player_id", player, at)
+			if goal.AssistPlayerID != nil {
+				checkOnPitch(field+".assist_player_id", playersByID[*goal.AssistPlayerID], at)
+			}
+			if goal.SecondAssistPlayerID != nil {
+				checkOnPitch(field+".second_assist_player_id", playersByID[*goal.SecondAssistPlayerID], at)
+			}
+		} else if sentOffAt, sentOff := dismissals[goal.PlayerID]; sentOff && at > sentOffAt {
+			errs.add(field+".player_id", "%s had already been sent off", player.Name)
+		}
+
+		var credited uint
+		switch player.TeamID {
+		case match.HomeTeamID:
+			credited = match.HomeTeamID
+			if goal.IsOwnGoal() {
+				credited = match.AwayTeamID
+			}
+		case match.AwayTeamID:
+			credited = match.AwayTeamID
+			if goal.IsOwnGoal() {
+				credited = match.HomeTeamID
+			}
+		default:
+			errs.add(field+".player_id", "%s does not play for either team", player.Name)
+			continue
+		}
+
+		if goal.TeamID != 0 && goal.TeamID != credited {
+			if goal.IsOwnGoal() {
+				errs.add(field+".team_id", "an own goal by %s counts for the opposing team", player.Name)
+			} else {
+				errs.add(field+".team_id", "%s does not play for the credited team", player.Name)
+			}
+			continue
+		}
+		result.Goals[i].TeamID = credited
+		validateAssists(goal, credited, playersByID, field, &errs)
+
+		if credited == match.HomeTeamID {
+			homeGoals++
+			if goal.Minute > 90 {
+				homeExtraTimeGoals++
+			}
+		} else {
+			awayGoals++
+			if goal.Minute > 90 {
+				awayExtraTimeGoals++
+			}
+		}
+	}
+
+	if homeGoals != result.HomeScore {
+		errs.add("home_score", "score is %d but %d goals are listed for the home team", result.HomeScore, homeGoals)
+	}
+	if awayGoals != result.AwayScore {
+		errs.add("away_score", "score is %d but %d goals are listed for the away team", result.AwayScore, awayGoals)
+	}
+	if extraTime {
+		if homeExtraTimeGoals != *result.HomeExtraTimeScore {
+			errs.add("home_extra_time_score", "extra time score is %d but %d home goals are listed after minute 90", *result.HomeExtraTimeScore, homeExtraTimeGoals)
+		}
+		if awayExtraTimeGoals != *result.AwayExtraTimeScore {
+			errs.add("away_extra_time_score", "extra time score is %d but %d away goals are listed after minute 90", *result.AwayExtraTimeScore, awayExtraTimeGoals)
+		}
+	}
+
+	if err := s.validateEligibility(match, *result, playersByID, &errs); err != nil {
+		return err
+	}
+
+	return errs.err()
+}
+
+// pitchTimes works out when each player was on the pitch for the teams that
+// submitted a lineup, taking the sendings off from the reported result.
+func (s *matchService) pitchTimes(match *models.Match, result MatchResult, extraTime bool) (map[uint]appearance, map[uint]bool, error) {
+	lineup, err := s.lineupRepo.FindByMatch(match.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(lineup) == 0 {
+		return nil, nil, nil
+	}
+	subs, err := s.lineupRepo.FindSubstitutions(match.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	teams := make(map[uint]bool)
+	for _, entry := range lineup {
+		teams[entry.TeamID] = true
+	}
+
+	length := 90
+	if extraTime {
+		length = 120
+	}
+	return appearances(lineup, subs, result.Cards, length), teams, nil
+}
+
+// validateCards checks the bookings of a result and fills in the team of each
+// card. It returns when every dismissed player was sent off, as an eventTime.
+func validateCards(match *models.Match, result *MatchResult, playersByID map[uint]models.Player, extraTime bool, errs *ValidationErrors) map[uint]int {
+	yellows := make(map[uint]bool)
+	dismissals := make(map[uint]int)
+
+	for i, card := range result.Cards {
+		field := fmt.Sprintf("cards[%d]", i)
+
+		validateMinute(field, card.Minute, card.AddedTime, extraTime, errs)
+		if i > 0 {
+			prev := result.Cards[i-1]
+			if eventTime(card.Minute, card.AddedTime) < eventTime(prev.Minute, prev.AddedTime) {
+				errs.add(field+".minute", "cards must be listed in order, %s comes before %s",
+					formatMinute(card.Minute, card.AddedTime), formatMinute(prev.Minute, prev.AddedTime))
+			}
+		}
+
+		player, ok := playersByID[card.PlayerID]
+		if !ok {
+			errs.add(field+".player_id", "player %d not found", card.PlayerID)
+			continue
+		}
+		if player.TeamID != match.HomeTeamID && player.TeamID != match.AwayTeamID {
+			errs.add(field+".player_id", "%s does not play for either team", player.Name)
+			continue
+		}
+		result.Cards[i].TeamID = player.TeamID
+
+		if _, sentOff := dismissals[card.PlayerID]; sentOff {
+			errs.add(field+".player_id", "%s had already been sent off", player.Name)
+			continue
+		}
+
+		switch card.Type {
+		case models.YellowCard:
+			if yellows[card.PlayerID] {
+				errs.add(field+".type", "%s already has a yellow card, report the second one as second_yellow", player.Name)
+				continue
+			}
+			yellows[card.PlayerID] = true
+		case models.SecondYellowCard:
+			if !yellows[card.PlayerID] {
+				errs.add(field+".type", "%s has no earlier yellow card", player.Name)
+				continue
+			}
+			dismissals[card.PlayerID] = eventTime(card.Minute, card.AddedTime)
+		case models.RedCard:
+			dismissals[card.PlayerID] = eventTime(card.Minute, card.AddedTime)
+		default:
+			errs.add(field+".type", "unknown card type %q", card.Type)
+		}
+	}
+
+	return dismissals
+}
+
+// validateEligibility rejects players who appear in a result while serving a
+// suspension for that match.
+func (s *matchService) validateEligibility(match *models.Match, result MatchResult, playersByID map[uint]models.Player, errs *ValidationErrors) error {
+	suspended, err := suspendedPlayers(s.repo, s.cardRepo, match)
+	if err != nil || len(suspended) == 0 {
+		return err
+	}
+
+	check := func(field string, playerID uint) {
+		if reason, ok := suspended[playerID]; ok {
+			errs.add(field, "%s is suspended for this match (%s)", playersByID[playerID].Name, reason)
+		}
+	}
+	for i, goal := range result.Goals {
+		field := fmt.Sprintf("goals[%d]", i)
+		check(field+".player_id", goal.PlayerID)
+		if goal.AssistPlayerID != nil {
+			check(field+".assist_player_id", *goal.AssistPlayerID)
+		}
+		if goal.SecondAssistPlayerID != nil {
+			check(field+".second_assist_player_id", *goal.SecondAssistPlayerID)
+		}
+	}
+	for i, card := range result.Cards {
+		check(fmt.Sprintf("cards[%d].player_id", i), card.PlayerID)
+	}
+
+	return nil
+}
+
+// validateAssists checks that the assisting players of a goal exist and play
+// for the team the goal is credited to.
+func validateAssists(goal models.Goal, credited uint, playersByID map[uint]models.Player, field string, errs *ValidationErrors) {
+	if goal.AssistPlayerID == nil {
+		if goal.SecondAssistPlayerID != nil {
+			errs.add(field+".second_assist_player_id", "a second assist requires an assist")
+		}
+		return
+	}
+	if goal.IsOwnGoal() {
+		errs.add(field+".assist_player_id", "own goals cannot be assisted")
+		return
+	}
+
+	type assist struct {
+		field    string
+		playerID uint
+	}
+	assists := []assist{{".assist_player_id", *goal.AssistPlayerID}}
+	if goal.SecondAssistPlayerID != nil {
+		if *goal.SecondAssistPlayerID == *goal.AssistPlayerID {
+			errs.add(field+".second_assist_player_id", "must differ from the assist")
+		}
+		assists = append(assists, assist{".second_assist_player_id", *goal.SecondAssistPlayerID})
+	}
+
+	for _, a := range assists {
+		player, ok := playersByID[a.playerID]
+		switch {
+		case a.playerID == goal.PlayerID:
+			errs.add(field+a.field, "the scorer cannot assist their own goal")
+		case !ok:
+			errs.add(field+a.field, "player %d not found", a.playerID)
+		case player.TeamID != credited:
+			errs.add(field+a.field, "%s does not play for the credited team", player.Name)
+		}
+	}
+}
+
+// validateKnockoutResult checks the extra-time and penalty fields of a result.
+// Whether a shootout was actually needed is decided once the whole tie is
+// known, see tieWinner.
+func validateKnockoutResult(match *models.Match, result MatchResult, errs *ValidationErrors) {
+	hasExtraTime := result.HomeExtraTimeScore != nil || result.AwayExtraTimeScore != nil
+	hasPenalties := result.HomePenalties != nil || result.AwayPenalties != nil
+
+	if match.TieID == nil && (hasExtraTime || hasPenalties) {
+		errs.add("extra_time", "extra time and penalties can only be reported for knockout ties")
+		return
+	}
+
+	if hasExtraTime {
+		if result.HomeExtraTimeScore == nil || result.AwayExtraTimeScore == nil {
+			errs.add("extra_time", "extra time score is required for both teams")
+		} else if *result.HomeExtraTimeScore > result.HomeScore || *result.AwayExtraTimeScore > result.AwayScore {
+			errs.add("extra_time", "extra time goals cannot exceed the final score")
+		}
+	}
+
+	if hasPenalties {
+		if result.HomePenalties == nil || result.AwayPenalties == nil {
+			errs.add("penalties", "penalty shootout score is required for both teams")
+		} else if *result.HomePenalties == *result.AwayPenalties {
+			errs.add("penalties", "penalty shootout cannot end level")
+		}
+	}
+}
+
+// validateMinute checks the minute and stoppage time of a goal or card.
+func validateMinute(field string, minute, addedTime int, extraTime bool, errs *ValidationErrors) {
+	switch {
+	case minute < 1 || minute > 120:
+		errs.add(field+".minute", "must be between 1 and 120, use added_time for stoppage time")
+	case minute > 90 && !extraTime:
+		errs.add(field+".minute", "minute %d is in extra time but no extra time was reported", minute)
+	}
+	if addedTime > 0 && minute != 45 && minute != 90 && minute != 105 && minute != 120 {
+		errs.add(field+".added_time", "stoppage time can only follow minute 45, 90, 105 or 120")
+	}
+}
+
+// eventTime orders match events by minute and then stoppage time.
+func eventTime(minute, addedTime int) int {
+	return minute*100 + addedTime
+}
+
+// formatMinute formats a minute the way it is announced, e.g. 45+2.
+func formatMinute(minute, addedTime int) string {
+	if addedTime > 0 {
+		return fmt.Sprintf("%d+%d", minute, addedTime)
+	}
+	return fmt.Sprintf("%d", minute)
+}
+
+func (s *matchService) GenerateFixtures(seasonID uint, opts FixtureOptions) ([]models.Match, error) {
+	if _, err := s.seasonRepo.FindByID(seasonID); err != nil {
+		return nil, errors.New("season not found")
+	}
+
+	if opts.CompetitionID != nil {
+		if _, err := s.competitionRepo.FindByID(*opts.CompetitionID); err != nil {
+			return nil, errors.New("competition not found")
+		}
+	}
+
+	if len(opts.TeamIDs) < 2 {
+		return nil, errors.New("at least two teams are required")
+	}
+
+	// Validate teams exist and are not listed twice
+	teams := make(map[uint]models.Team, len(opts.TeamIDs))
+	for _, id := range opts.TeamIDs {
+		if _, ok := teams[id]; ok {
+			return nil, fmt.Errorf("team %d is listed more than once", id)
+		}
+		team, err := s.teamRepo.FindByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("team %d not found", id)
+		}
+		teams[id] = *team
+	}
+
+	interval := opts.IntervalDays
+	if interval <= 0 {
+		interval = 7
+	}
+
+	rounds := roundRobin(opts.TeamIDs)
+	if opts.DoubleRoundRobin {
+		// Second half mirrors the first with home and away swapped
+		firstHalf := rounds
+		for _, round := range firstHalf {
+			mirrored := make([][2]uint, len(round))
+			for i, pair := range round {
+				mirrored[i] = [2]uint{pair[1], pair[0]}
+			}
+			rounds = append(rounds, mirrored)
+		}
+	}
+
+	var fixtures []models.Match
+	for i, round := range rounds {
+		matchTime := opts.StartDate.AddDate(0, 0, i*interval)
+		for _, pair := range round {
+			season := seasonID
+			fixtures = append(fixtures, models.Match{
+				MatchTime:     matchTime,
+				HomeTeamID:    pair[0],
+				AwayTeamID:    pair[1],
+				Status:        models.Scheduled,
+				SeasonID:      &season,
+				CompetitionID: opts.CompetitionID,
+				Matchday:      i + 1,
+			})
+		}
+	}
+
+	if opts.DryRun {
+		// Attach team details so the proposal is readable without IDs lookups
+		for i := range fixtures {
+			fixtures[i].HomeTeam = teams[fixtures[i].HomeTeamID]
+			fixtures[i].AwayTeam = teams[fixtures[i].AwayTeamID]
+		}
+		return fixtures, nil
+	}
+
+	err := s.repo.WithTransaction(func(repo repositories.MatchRepository) error {
+		txService := &matchService{
+			repo:            repo,
+			goalRepo:        s.goalRepo,
+			playerRepo:      s.playerRepo,
+			cardRepo:        s.cardRepo,
+			lineupRepo:      s.lineupRepo,
+			contractRepo:    s.contractRepo,
+			teamRepo:        s.teamRepo,
+			seasonRepo:      s.seasonRepo,
+			competitionRepo: s.competitionRepo,
+		}
+		for i := range fixtures {
+			if err := txService.CreateMatch(&fixtures[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fixtures, nil
+}
+
+// roundRobin pairs every team with every other team once using the circle
+// method. Each round is a list of [home, away] pairs. With an odd number of
+// teams a bye is pinned to the fixed slot, so whoever meets it sits out.
+// Flipping the fixed pairing on odd rounds and every other pairing by its
+// position keeps home and away games balanced for each team.
+func roundRobin(teamIDs []uint) [][][2]uint {
+	const bye = 0
+
+	slots := append([]uint{}, teamIDs...)
+	if len(slots)%2 == 1 {
+		slots = append([]uint{bye}, slots...)
+	}
+
+	n := len(slots)
+	rounds := make([][][2]uint, 0, n-1)
+	for r := 0; r < n-1; r++ {
+		round := make([][2]uint, 0, n/2)
+		for i := 0; i < n/2; i++ {
+			home, away := slots[i], slots[n-1-i]
+			if (i == 0 && r%2 == 1) || (i > 0 && i%2 == 1) {
+				home, away = away, home
+			}
+			if home == bye || away == bye {
+				continue
+			}
+			round = append(round, [2]uint{home, away})
+		}
+		rounds = append(rounds, round)
+
+		// Keep the first slot fixed and rotate the rest clockwise
+		slots = append([]uint{slots[0], slots[n-1]}, slots[1:n-1]...)
+	}
+
+	return rounds
 }
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
+)
+
+func TestApplyAwardedScore(t *testing.T) {
+	home, away, other := uint(1), uint(2), uint(3)
+
+	tests := []struct {
+		name      string
+		change    StatusChange
+		wantHome  int
+		wantAway  int
+		wantError bool
+	}{
+		{
+			name:     "neither score awards 3-0 at home",
+			change:   StatusChange{WinnerTeamID: &home},
+			wantHome: 3, wantAway: 0,
+		},
+		{
+			name:     "neither score awards 0-3 away",
+			change:   StatusChange{WinnerTeamID: &away},
+			wantHome: 0, wantAway: 3,
+		},
+		{
+			name:     "both scores",
+			change:   StatusChange{WinnerTeamID: &home, HomeScore: intPtr(2), AwayScore: intPtr(1)},
+			wantHome: 2, wantAway: 1,
+		},
+		{
+			name:      "only the home score",
+			change:    StatusChange{WinnerTeamID: &home, HomeScore: intPtr(5)},
+			wantError: true,
+		},
+		{
+			name:      "only the away score",
+			change:    StatusChange{WinnerTeamID: &away, AwayScore: intPtr(5)},
+			wantError: true,
+		},
+		{
+			name:      "scores against the winner",
+			change:    StatusChange{WinnerTeamID: &away, HomeScore: intPtr(2), AwayScore: intPtr(1)},
+			wantError: true,
+		},
+		{
+			name:      "level scores",
+			change:    StatusChange{WinnerTeamID: &home, HomeScore: intPtr(1), AwayScore: intPtr(1)},
+			wantError: true,
+		},
+		{
+			name:      "no winner",
+			change:    StatusChange{HomeScore: intPtr(3), AwayScore: intPtr(0)},
+			wantError: true,
+		},
+		{
+			name:      "winner not in the match",
+			change:    StatusChange{WinnerTeamID: &other},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := finishedMatch(1, home, away, 1, 1)
+			match.HomeExtraTimeScore, match.AwayExtraTimeScore = intPtr(0), intPtr(0)
+			match.HomePenalties, match.AwayPenalties = intPtr(4), intPtr(3)
+
+			err := applyAwardedScore(&match, tt.change)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("applyAwardedScore() succeeded with %d-%d, want an error", *match.HomeScore, *match.AwayScore)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyAwardedScore() error = %v", err)
+			}
+			if *match.HomeScore != tt.wantHome || *match.AwayScore != tt.wantAway {
+				t.Errorf("score = %d-%d, want %d-%d", *match.HomeScore, *match.AwayScore, tt.wantHome, tt.wantAway)
+			}
+			if match.HomeExtraTimeScore != nil || match.HomePenalties != nil {
+				t.Error("extra time and penalties were kept on an awarded match")
+			}
+		})
+	}
+}
+
+func TestMatchServiceChangeStatusFrom(t *testing.T) {
+	db := newTestDB(t)
+	home, away := &models.Team{Name: "Alpha"}, &models.Team{Name: "Beta"}
+	if err := db.Create(home).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(away).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewMatchService(
+		repositories.NewMatchRepository(db),
+		repositories.NewGoalRepository(db),
+		repositories.NewPlayerRepository(db),
+		repositories.NewCardRepository(db),
+		repositories.NewLineupRepository(db),
+		repositories.NewContractRepository(db),
+		repositories.NewTeamRepository(db),
+		repositories.NewSeasonRepository(db),
+		repositories.NewCompetitionRepository(db),
+	)
+
+	kickoff := []models.MatchStatus{models.Scheduled, models.Postponed}
+	resume := []models.MatchStatus{models.HalfTime}
+
+	tests := []struct {
+		name    string
+		status  models.MatchStatus
+		from    []models.MatchStatus
+		wantErr bool
+	}{
+		{"kick off a scheduled match", models.Scheduled, kickoff, false},
+		{"kick off a postponed match", models.Postponed, kickoff, false},
+		{"kick off at half time", models.HalfTime, kickoff, true},
+		{"resume at half time", models.HalfTime, resume, false},
+		{"resume a scheduled match", models.Scheduled, resume, true},
+		{"resume a postponed match", models.Postponed, resume, true},
+		{"go live from any allowed state", models.HalfTime, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := &models.Match{
+				MatchTime:  time.Date(2025, 8, 1, 15, 0, 0, 0, time.UTC),
+				HomeTeamID: home.ID,
+				AwayTeamID: away.ID,
+				Status:     tt.status,
+			}
+			if err := db.Create(match).Error; err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := svc.ChangeStatus(match.ID, StatusChange{Status: models.Live, From: tt.from})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ChangeStatus() from %s error = %v, want error %v", tt.status, err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Status != models.Live {
+				t.Errorf("status = %s, want %s", got.Status, models.Live)
+			}
+		})
+	}
+}
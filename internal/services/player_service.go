@@ -2,26 +2,50 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"xyz-football/internal/models"
 	"xyz-football/internal/repositories"
+
+	"gorm.io/gorm"
 )
 
 type PlayerService interface {
 	CreatePlayer(player *models.Player) error
-	GetAllPlayers() ([]models.Player, error)
+	ListPlayers(filter repositories.PlayerListFilter, opts repositories.ListOptions) ([]models.Player, int64, error)
 	GetPlayerByID(id uint) (*models.Player, error)
 	GetPlayersByTeam(teamID uint) ([]models.Player, error)
 	UpdatePlayer(player *models.Player) error
 	DeletePlayer(id uint) error
+	TransferPlayer(id uint, transfer Transfer) (*models.Player, error)
+	GetContracts(id uint) ([]models.PlayerContract, error)
 }
 
 type playerService struct {
-	repo repositories.PlayerRepository
+	repo         repositories.PlayerRepository
+	contractRepo repositories.ContractRepository
+	teamRepo     repositories.TeamRepository
 }
 
-func NewPlayerService(repo repositories.PlayerRepository) PlayerService {
-	return &playerService{repo: repo}
+// Transfer moves a player to another team. Number is the shirt number at the
+// new team and defaults to the current one.
+type Transfer struct {
+	TeamID uint
+	Date   time.Time
+	Number int
+}
+
+func NewPlayerService(
+	repo repositories.PlayerRepository,
+	contractRepo repositories.ContractRepository,
+	teamRepo repositories.TeamRepository,
+) PlayerService {
+	return &playerService{
+		repo:         repo,
+		contractRepo: contractRepo,
+		teamRepo:     teamRepo,
+	}
 }
 
 func (s *playerService) CreatePlayer(player *models.Player) error {
@@ -40,8 +64,8 @@ func (s *playerService) CreatePlayer(player *models.Player) error {
 	return s.repo.Create(player)
 }
 
-func (s *playerService) GetAllPlayers() ([]models.Player, error) {
-	return s.repo.FindAll()
+func (s *playerService) ListPlayers(filter repositories.PlayerListFilter, opts repositories.ListOptions) ([]models.Player, int64, error) {
+	return s.repo.List(filter, opts)
 }
 
 func (s *playerService) GetPlayerByID(id uint) (*models.Player, error) {
@@ -54,11 +78,16 @@ func (s *playerService) GetPlayersByTeam(teamID uint) ([]models.Player, error) {
 
 func (s *playerService) UpdatePlayer(player *models.Player) error {
 	// Check if player exists
-	_, err := s.repo.FindByID(player.ID)
+	existing, err := s.repo.FindByID(player.ID)
 	if err != nil {
 		return errors.New("player not found")
 	}
 
+	// Moving teams has to go through a transfer so the history is kept
+	if player.TeamID != existing.TeamID {
+		return errors.New("use the transfer endpoint to move a player to another team")
+	}
+
 	// Validate player number is unique within team (if number is being updated)
 	existingPlayers, err := s.repo.FindByTeam(player.TeamID)
 	if err != nil {
@@ -83,3 +112,69 @@ func (s *playerService) DeletePlayer(id uint) error {
 
 	return s.repo.Delete(id)
 }
+
+func (s *playerService) TransferPlayer(id uint, transfer Transfer) (*models.Player, error) {
+	player, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("player not found")
+	}
+
+	if transfer.TeamID == player.TeamID {
+		return nil, errors.New("player is already registered with this team")
+	}
+	if _, err := s.teamRepo.FindByID(transfer.TeamID); err != nil {
+		return nil, errors.New("team not found")
+	}
+
+	if transfer.Date.IsZero() {
+		transfer.Date = time.Now()
+	}
+	if transfer.Date.After(time.Now()) {
+		return nil, errors.New("transfer date cannot be in the future")
+	}
+
+	contracts, err := s.contractRepo.FindByPlayer(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, contract := range contracts {
+		if contract.EndDate == nil && contract.StartDate != nil && !transfer.Date.After(*contract.StartDate) {
+			return nil, errors.New("transfer date must be after the start of the current contract")
+		}
+	}
+	// Back-dating past a match would credit it to the new team
+	last, err := s.contractRepo.FindLastAppearance(id, time.Now())
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if last != nil && !transfer.Date.After(last.MatchTime) {
+		return nil, fmt.Errorf("transfer date must be after the player's last match on %s", last.MatchTime.Format(time.RFC3339))
+	}
+
+	if transfer.Number == 0 {
+		transfer.Number = player.Number
+	}
+	squad, err := s.repo.FindByTeam(transfer.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range squad {
+		if p.Number == transfer.Number {
+			return nil, errors.New("player number already exists in this team")
+		}
+	}
+
+	if err := s.contractRepo.Transfer(player, transfer.TeamID, transfer.Number, transfer.Date); err != nil {
+		return nil, err
+	}
+
+	return player, nil
+}
+
+func (s *playerService) GetContracts(id uint) ([]models.PlayerContract, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return nil, errors.New("player not found")
+	}
+
+	return s.contractRepo.FindByPlayer(id)
+}
@@ -1,172 +1,528 @@
 package services
 
 import (
+	"errors"
+	"fmt"
 	"sort"
+	"strconv"
 	"time"
 
 	"xyz-football/internal/models"
 	"xyz-football/internal/repositories"
+
+	"gorm.io/gorm"
 )
 
 type ReportService interface {
-	GetStandings() ([]TeamStanding, error)
-	GetTopScorers(limit int) ([]PlayerGoals, error)
+	GetStandings(filter ReportFilter) ([]TeamStanding, error)
+	GetStandingsHistory(teamID uint, filter ReportFilter) ([]StandingsSnapshot, error)
+	GetTopScorers(limit int, filter ReportFilter) ([]PlayerGoals, error)
+	GetTopAssists(limit int, filter ReportFilter) ([]PlayerAssists, error)
+	GetGoalContributions(limit int, filter ReportFilter) ([]PlayerContributions, error)
 	GetMatchReport(matchID uint) (*MatchReport, error)
+	GetMatchReports(filter ReportFilter) ([]MatchReport, error)
+	GetHeadToHead(teamA, teamB uint, limit int, filter ReportFilter) (*HeadToHead, error)
 }
 
 type reportService struct {
-	repo     repositories.MatchRepository
-	teamRepo repositories.TeamRepository
+	repo            repositories.MatchRepository
+	lineupRepo      repositories.LineupRepository
+	teamRepo        repositories.TeamRepository
+	competitionRepo repositories.CompetitionRepository
+}
+
+var (
+	// ErrCompetitionNotFound is returned when a report is scoped to a
+	// competition that does not exist.
+	ErrCompetitionNotFound = errors.New("competition not found")
+	// ErrTeamNotInStandings is returned for the position history of a team
+	// the selected standings do not include.
+	ErrTeamNotInStandings = errors.New("team is not part of these standings")
+)
+
+// ReportFilter scopes a report to a season and/or competition. Friendlies are
+// left out unless explicitly requested or the competition itself is selected.
+// TeamID and TieBreak only apply to the player leaderboards and statistics.
+// AsOf, Venue and LastMatches only apply to the standings: AsOf leaves out
+// matches whose result came in later (by their status history, or their
+// kick-off if it has none), Venue keeps each team's home or away matches and
+// LastMatches, when set, each team's last that many.
+type ReportFilter struct {
+	SeasonID          *uint
+	CompetitionID     *uint
+	TeamID            *uint
+	IncludeFriendlies bool
+	TieBreak          LeaderboardTieBreak
+	AsOf              *time.Time
+	Venue             Venue
+	LastMatches       int
+}
+
+// Venue selects the matches of a home or away table.
+type Venue string
+
+const (
+	VenueAll  Venue = "all"
+	VenueHome Venue = "home"
+	VenueAway Venue = "away"
+)
+
+// LeaderboardTieBreak orders players level on a leaderboard.
+type LeaderboardTieBreak string
+
+const (
+	TieBreakByName    LeaderboardTieBreak = "name"
+	TieBreakByMinutes LeaderboardTieBreak = "minutes" // fewer minutes played first, then name
+)
+
+func (f ReportFilter) matchFilter() repositories.MatchFilter {
+	return repositories.MatchFilter{
+		SeasonID:          f.SeasonID,
+		CompetitionID:     f.CompetitionID,
+		ExcludeFriendlies: !f.IncludeFriendlies && f.CompetitionID == nil,
+	}
 }
 
 type TeamStanding struct {
-	TeamID    uint   `json:"team_id"`
-	TeamName  string `json:"team_name"`
-	Played    int    `json:"played"`
-	Won       int    `json:"won"`
-	Drawn     int    `json:"drawn"`
-	Lost      int    `json:"lost"`
-	GoalsFor  int    `json:"goals_for"`
-	GoalsAway int    `json:"goals_away"`
-	Points    int    `json:"points"`
+	Position       int    `json:"position"`
+	TeamID         uint   `json:"team_id"`
+	TeamName       string `json:"team_name"`
+	Played         int    `json:"played"`
+	Won            int    `json:"won"`
+	Drawn          int    `json:"drawn"`
+	Lost           int    `json:"lost"`
+	GoalsFor       int    `json:"goals_for"`
+	GoalsAway      int    `json:"goals_away"`
+	GoalDifference int    `json:"goal_difference"`
+	AwayGoalsFor   int    `json:"away_goals_for"`
+	Points         int    `json:"points"`
+	FairPlayPoints int    `json:"fair_play_points"`      // deducted for bookings, so 0 is the best record
+	TieBreaker     string `json:"tie_breaker,omitempty"` // criterion that decided the place among teams level on points
+	Form           string `json:"form"`                  // last results, oldest first, e.g. "WWDLW"
 }
 
+// StandingsSnapshot is a team's place in the table after a round of matches.
+// Matchday is only set when the rounds follow the fixture list's matchdays;
+// Date is the kick-off of the last match of the round.
+type StandingsSnapshot struct {
+	Round    int    `json:"round"`
+	Matchday int    `json:"matchday,omitempty"`
+	Date     string `json:"date"`
+	Position int    `json:"position"`
+	Played   int    `json:"played"`
+	Points   int    `json:"points"`
+}
+
+// PlayerGoals and the other leaderboard rows attribute a player's numbers to
+// the team they played for at the time, so a player who moved clubs within the
+// scope has a row for each.
 type PlayerGoals struct {
-	PlayerID   uint   `json:"player_id"`
-	PlayerName string `json:"player_name"`
-	TeamName   string `json:"team_name"`
-	Goals      int    `json:"goals"`
+	PlayerID      uint   `json:"player_id"`
+	PlayerName    string `json:"player_name"`
+	TeamID        uint   `json:"team_id"`
+	TeamName      string `json:"team_name"`
+	Goals         int    `json:"goals"`
+	MinutesPlayed int    `json:"minutes_played,omitempty"` // only filled when ties are broken by minutes
+}
+
+type PlayerAssists struct {
+	PlayerID      uint   `json:"player_id"`
+	PlayerName    string `json:"player_name"`
+	TeamID        uint   `json:"team_id"`
+	TeamName      string `json:"team_name"`
+	Assists       int    `json:"assists"`
+	MinutesPlayed int    `json:"minutes_played,omitempty"` // only filled when ties are broken by minutes
+}
+
+type PlayerContributions struct {
+	PlayerID      uint   `json:"player_id"`
+	PlayerName    string `json:"player_name"`
+	TeamID        uint   `json:"team_id"`
+	TeamName      string `json:"team_name"`
+	Goals         int    `json:"goals"`
+	Assists       int    `json:"assists"`
+	Contributions int    `json:"contributions"`
+	MinutesPlayed int    `json:"minutes_played,omitempty"` // only filled when ties are broken by minutes
 }
 
+// MatchReport sums up a match. HomeWinsUntilThisMatch and
+// AwayWinsUntilThisMatch count the wins each team had by the end of this
+// match, over the same scope as the report; TopScorer names the player who
+// scored most in the match, own goals aside. StatusAkhir and
+// TopScorerPlayer repeat the result and top scorer under the keys and
+// values of the legacy server, for its clients.
 type MatchReport struct {
-	MatchID    uint          `json:"match_id"`
-	HomeTeam   string        `json:"home_team"`
-	AwayTeam   string        `json:"away_team"`
-	HomeScore  *int          `json:"home_score,omitempty"`
-	AwayScore  *int          `json:"away_score,omitempty"`
-	MatchTime  string        `json:"match_time"`
-	Status     string        `json:"status"`
-	Goals      []Goal        `json:"goals,omitempty"`
-	TopScorers []PlayerGoals `json:"top_scorers,omitempty"`
+	MatchID                uint          `json:"match_id"`
+	HomeTeam               string        `json:"home_team"`
+	AwayTeam               string        `json:"away_team"`
+	HomeScore              *int          `json:"home_score,omitempty"`
+	AwayScore              *int          `json:"away_score,omitempty"`
+	HomeExtraTimeScore     *int          `json:"home_extra_time_score,omitempty"`
+	AwayExtraTimeScore     *int          `json:"away_extra_time_score,omitempty"`
+	HomePenalties          *int          `json:"home_penalties,omitempty"`
+	AwayPenalties          *int          `json:"away_penalties,omitempty"`
+	FinalScore             string        `json:"final_score"` // "0-0" until a result is in
+	Result                 string        `json:"result"`
+	MatchTime              string        `json:"match_time"`
+	Status                 string        `json:"status"`
+	TopScorer              *string       `json:"top_scorer,omitempty"`
+	StatusAkhir            string        `json:"status_akhir"`
+	TopScorerPlayer        *string       `json:"pencetak_gol_terbanyak,omitempty"`
+	HomeWinsUntilThisMatch int           `json:"home_wins_until_this_match"`
+	AwayWinsUntilThisMatch int           `json:"away_wins_until_this_match"`
+	Goals                  []Goal        `json:"goals,omitempty"`
+	TopScorers             []PlayerGoals `json:"top_scorers,omitempty"`
 }
 
+// Textual results of a match report.
+const (
+	ResultHomeWin            = "Home Win"
+	ResultAwayWin            = "Away Win"
+	ResultDraw               = "Draw"
+	ResultHomeWinOnPenalties = "Home Win on Penalties"
+	ResultAwayWinOnPenalties = "Away Win on Penalties"
+	ResultNotPlayed          = "Not Played"
+)
+
+// Results as the legacy server worded them, sent as status_akhir. A tie
+// settled on penalties is a draw there.
+const (
+	LegacyResultHomeWin   = "Tim Home Menang"
+	LegacyResultAwayWin   = "Tim Away Menang"
+	LegacyResultDraw      = "Draw"
+	LegacyResultScheduled = "Scheduled"
+)
+
 type Goal struct {
 	PlayerName string `json:"player_name"`
+	TeamName   string `json:"team_name"` // team credited with the goal
+	Type       string `json:"type"`
 	Minute     int    `json:"minute"`
+	AddedTime  int    `json:"added_time,omitempty"`
 	IsOwnGoal  bool   `json:"is_own_goal"`
 }
 
-func NewReportService(matchRepo repositories.MatchRepository, teamRepo repositories.TeamRepository) ReportService {
+// HeadToHead is the record of the meetings between two teams that have a
+// final result. Each side's record is split by where it played; a tie
+// settled on penalties counts as a draw.
+type HeadToHead struct {
+	TeamA       HeadToHeadSide      `json:"team_a"`
+	TeamB       HeadToHeadSide      `json:"team_b"`
+	Meetings    int                 `json:"meetings"`
+	Draws       int                 `json:"draws"`
+	TopScorers  []PlayerGoals       `json:"top_scorers"`
+	LastMeeting *HeadToHeadMeeting  `json:"last_meeting,omitempty"`
+	Matches     []HeadToHeadMeeting `json:"matches"`
+}
+
+type HeadToHeadSide struct {
+	TeamID   uint       `json:"team_id"`
+	TeamName string     `json:"team_name"`
+	Overall  TeamRecord `json:"overall"`
+	Home     TeamRecord `json:"home"`
+	Away     TeamRecord `json:"away"`
+}
+
+type HeadToHeadMeeting struct {
+	MatchID      uint   `json:"match_id"`
+	MatchTime    string `json:"match_time"`
+	Competition  string `json:"competition,omitempty"`
+	HomeTeam     string `json:"home_team"`
+	AwayTeam     string `json:"away_team"`
+	Score        string `json:"score"`
+	WinnerTeamID *uint  `json:"winner_team_id,omitempty"`
+}
+
+func NewReportService(
+	matchRepo repositories.MatchRepository,
+	lineupRepo repositories.LineupRepository,
+	teamRepo repositories.TeamRepository,
+	competitionRepo repositories.CompetitionRepository,
+) ReportService {
 	return &reportService{
-		repo:     matchRepo,
-		teamRepo: teamRepo,
+		repo:            matchRepo,
+		lineupRepo:      lineupRepo,
+		teamRepo:        teamRepo,
+		competitionRepo: competitionRepo,
+	}
+}
+
+func (s *reportService) GetStandings(filter ReportFilter) ([]TeamStanding, error) {
+	table, err := s.leagueTable(filter)
+	if err != nil {
+		return nil, err
 	}
+	return table.standings(table.results), nil
 }
 
-func (s *reportService) GetStandings() ([]TeamStanding, error) {
-	teams, err := s.teamRepo.FindAll()
+// GetStandingsHistory replays the table one matchday at a time and records
+// the team's place after each. Matches are grouped by their matchday when
+// every one of them has one, and by calendar day otherwise.
+func (s *reportService) GetStandingsHistory(teamID uint, filter ReportFilter) ([]StandingsSnapshot, error) {
+	table, err := s.leagueTable(filter)
 	if err != nil {
 		return nil, err
 	}
 
-	standings := make(map[uint]*TeamStanding)
+	found := false
+	for _, team := range table.teams {
+		found = found || team.TeamID == teamID
+	}
+	if !found {
+		return nil, ErrTeamNotInStandings
+	}
 
-	// Initialize standings for all teams
-	for _, team := range teams {
-		standings[team.ID] = &TeamStanding{
-			TeamID:    team.ID,
-			TeamName:  team.Name,
-			Played:    0,
-			Won:       0,
-			Drawn:     0,
-			Lost:      0,
-			GoalsFor:  0,
-			GoalsAway: 0,
-			Points:    0,
+	byMatchday := len(table.results) > 0
+	for _, match := range table.results {
+		byMatchday = byMatchday && match.Matchday > 0
+	}
+
+	// Rounds in the order they were first played
+	type round struct {
+		matchday int
+		date     string
+		last     time.Time
+	}
+	var rounds []*round
+	index := make(map[string]*round)
+	for _, match := range table.results {
+		key := match.MatchTime.UTC().Format("2006-01-02")
+		if byMatchday {
+			key = strconv.Itoa(match.Matchday)
 		}
+		r, ok := index[key]
+		if !ok {
+			r = &round{date: match.MatchTime.UTC().Format("2006-01-02")}
+			if byMatchday {
+				r.matchday = match.Matchday
+			}
+			index[key] = r
+			rounds = append(rounds, r)
+		}
+		if match.MatchTime.After(r.last) {
+			r.last = match.MatchTime
+		}
+	}
+	if byMatchday {
+		sort.SliceStable(rounds, func(i, j int) bool {
+			return rounds[i].matchday < rounds[j].matchday
+		})
 	}
 
-	// Process all finished matches
-	matches, err := s.repo.FindAll()
+	history := make([]StandingsSnapshot, 0, len(rounds))
+	for i, r := range rounds {
+		var played []models.Match
+		for _, match := range table.results {
+			if (byMatchday && match.Matchday <= r.matchday) ||
+				(!byMatchday && match.MatchTime.UTC().Format("2006-01-02") <= r.date) {
+				played = append(played, match)
+			}
+		}
+
+		for _, row := range table.standings(played) {
+			if row.TeamID != teamID {
+				continue
+			}
+			history = append(history, StandingsSnapshot{
+				Round:    i + 1,
+				Matchday: r.matchday,
+				Date:     r.last.Format(time.RFC3339),
+				Position: row.Position,
+				Played:   row.Played,
+				Points:   row.Points,
+			})
+		}
+	}
+
+	return history, nil
+}
+
+// leagueTable holds what a standings table is built from: a blank row for
+// every team in the table and the results between them, in kick-off order.
+type leagueTable struct {
+	teams   []TeamStanding
+	results []models.Match
+	rules   models.StandingsRules
+	venue   Venue
+	last    int
+}
+
+func (s *reportService) leagueTable(filter ReportFilter) (*leagueTable, error) {
+	matches, err := s.repo.FindByFilter(filter.matchFilter())
 	if err != nil {
 		return nil, err
 	}
 
+	table := &leagueTable{venue: filter.Venue, last: filter.LastMatches}
+	if table.venue == "" {
+		table.venue = VenueAll
+	}
+	listed := make(map[uint]bool)
+	addTeam := func(id uint, name string) {
+		if !listed[id] {
+			listed[id] = true
+			table.teams = append(table.teams, TeamStanding{TeamID: id, TeamName: name})
+		}
+	}
+
+	if filter.SeasonID == nil && filter.CompetitionID == nil {
+		// Unscoped table: every team gets a row
+		teams, err := s.teamRepo.FindAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, team := range teams {
+			addTeam(team.ID, team.Name)
+		}
+	} else {
+		// Scoped table: only teams with a fixture in the season/competition
+		for _, match := range matches {
+			addTeam(match.HomeTeamID, match.HomeTeam.Name)
+			addTeam(match.AwayTeamID, match.AwayTeam.Name)
+		}
+	}
+
+	if table.rules, err = s.standingsRules(filter); err != nil {
+		return nil, err
+	}
+
+	var resultTimes map[uint]time.Time
+	if filter.AsOf != nil {
+		ids := make([]uint, len(matches))
+		for i, match := range matches {
+			ids[i] = match.ID
+		}
+		if resultTimes, err = s.repo.FindResultTimes(ids); err != nil {
+			return nil, err
+		}
+	}
+
 	for _, match := range matches {
-		if match.Status != models.Finished || match.HomeScore == nil || match.AwayScore == nil {
+		// Only finished and awarded matches count; cancelled, abandoned and
+		// postponed fixtures are left out
+		if !match.Status.HasFinalResult() || match.HomeScore == nil || match.AwayScore == nil {
 			continue
 		}
+		if filter.AsOf != nil {
+			resultAt, ok := resultTimes[match.ID]
+			if !ok {
+				resultAt = match.MatchTime
+			}
+			if resultAt.After(*filter.AsOf) {
+				continue
+			}
+		}
+		if !listed[match.HomeTeamID] || !listed[match.AwayTeamID] {
+			// One of the teams has been removed
+			continue
+		}
+		table.results = append(table.results, match)
+	}
 
-		home := standings[match.HomeTeamID]
-		away := standings[match.AwayTeamID]
+	return table, nil
+}
 
-		home.Played++
-		away.Played++
+// standings builds and ranks the table from the given results, counting for
+// each team only its matches at the table's venue and, for a form table, only
+// its last ones.
+func (t *leagueTable) standings(results []models.Match) []TeamStanding {
+	rows := make(map[uint]*TeamStanding, len(t.teams))
+	for _, team := range t.teams {
+		row := team
+		rows[team.TeamID] = &row
+	}
 
-		home.GoalsFor += *match.HomeScore
-		home.GoalsAway += *match.AwayScore
-		away.GoalsFor += *match.AwayScore
-		away.GoalsAway += *match.HomeScore
+	var sel selection
+	if t.venue != VenueAll || t.last > 0 {
+		sel = make(selection, len(t.teams))
+		for _, team := range t.teams {
+			var picked []teamResult
+			for _, r := range teamResults(results, team.TeamID) {
+				if t.venue == VenueAll || r.home == (t.venue == VenueHome) {
+					picked = append(picked, r)
+				}
+			}
+			if t.last > 0 && len(picked) > t.last {
+				picked = picked[len(picked)-t.last:]
+			}
+			sel[team.TeamID] = make(map[uint]bool, len(picked))
+			for _, r := range picked {
+				sel[team.TeamID][r.match.ID] = true
+			}
+		}
+	}
 
-		switch {
-		case *match.HomeScore > *match.AwayScore:
-			home.Won++
-			away.Lost++
-			home.Points += 3
-		case *match.HomeScore < *match.AwayScore:
-			away.Won++
-			home.Lost++
-			away.Points += 3
-		default:
-			home.Drawn++
-			away.Drawn++
-			home.Points++
-			away.Points++
+	for _, match := range results {
+		if sel.counts(match.HomeTeamID, match.ID) {
+			applySide(rows[match.HomeTeamID], match, true, t.rules)
+		}
+		if sel.counts(match.AwayTeamID, match.ID) {
+			applySide(rows[match.AwayTeamID], match, false, t.rules)
 		}
 	}
 
-	// Convert map to slice and sort by points, then goal difference, then goals for
-	result := make([]TeamStanding, 0, len(standings))
-	for _, standing := range standings {
-		result = append(result, *standing)
+	result := make([]TeamStanding, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, *row)
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		if result[i].Points != result[j].Points {
-			return result[i].Points > result[j].Points
-		}
-		iGD := result[i].GoalsFor - result[i].GoalsAway
-		jGD := result[j].GoalsFor - result[j].GoalsAway
-		if iGD != jGD {
-			return iGD > jGD
+	ranked := rankStandings(result, results, sel, t.rules)
+	for i := range ranked {
+		var counted []teamResult
+		for _, r := range teamResults(results, ranked[i].TeamID) {
+			if sel.counts(ranked[i].TeamID, r.match.ID) {
+				counted = append(counted, r)
+			}
 		}
-		return result[i].GoalsFor > result[j].GoalsFor
-	})
+		ranked[i].Form = formString(counted, defaultFormLength)
+	}
+	return ranked
+}
 
-	return result, nil
+// standingsRules returns the points system and tie-breakers of the selected
+// competition, or the defaults when the table is not scoped to one.
+func (s *reportService) standingsRules(filter ReportFilter) (models.StandingsRules, error) {
+	if filter.CompetitionID == nil {
+		return models.DefaultStandingsRules(), nil
+	}
+
+	competition, err := s.competitionRepo.FindByID(*filter.CompetitionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.StandingsRules{}, ErrCompetitionNotFound
+	}
+	if err != nil {
+		return models.StandingsRules{}, err
+	}
+	if competition.Rules == nil {
+		return models.DefaultStandingsRules(), nil
+	}
+	return *competition.Rules, nil
 }
 
-func (s *reportService) GetTopScorers(limit int) ([]PlayerGoals, error) {
-	// count goals by player across all matches
+func (s *reportService) GetTopScorers(limit int, filter ReportFilter) ([]PlayerGoals, error) {
+	// count goals by player across all matches in scope
 	type row struct {
 		PlayerID   uint
 		Goals      int64
 		PlayerName string
+		TeamID     uint
 		TeamName   string
 	}
 
 	var rows []row
-	err := s.repo.GetDB().Table("goals").
+	err := s.goalsInScope(filter).
 		Select(
 			"goals.player_id as player_id,"+
-			"COUNT(*) as goals,"+
-			"players.name as player_name,"+
-			"teams.name as team_name").
+				"COUNT(*) as goals,"+
+				"players.name as player_name,"+
+				"teams.id as team_id,"+
+				"teams.name as team_name").
 		Joins("JOIN players ON players.id = goals.player_id").
-		Joins("JOIN teams ON teams.id = players.team_id").
-		Group("goals.player_id, players.name, teams.name").
+		Joins("JOIN teams ON teams.id = goals.team_id").
+		Where("goals.type <> ?", models.OwnGoal).
+		Group("goals.player_id, players.name, teams.id, teams.name").
 		Order("goals DESC, players.name ASC").
-		Limit(limit).
+		Limit(sqlLimit(limit, filter)).
 		Scan(&rows).Error
 
 	if err != nil {
@@ -179,6 +535,7 @@ func (s *reportService) GetTopScorers(limit int) ([]PlayerGoals, error) {
 		result = append(result, PlayerGoals{
 			PlayerID:   row.PlayerID,
 			PlayerName: row.PlayerName,
+			TeamID:     row.TeamID,
 			TeamName:   row.TeamName,
 			Goals:      int(row.Goals),
 		})
@@ -189,32 +546,409 @@ func (s *reportService) GetTopScorers(limit int) ([]PlayerGoals, error) {
 		result = []PlayerGoals{}
 	}
 
+	if filter.TieBreak == TieBreakByMinutes {
+		minutes, err := s.minutesPlayed(filter)
+		if err != nil {
+			return nil, err
+		}
+		for i := range result {
+			result[i].MinutesPlayed = minutes[[2]uint{result[i].PlayerID, result[i].TeamID}]
+		}
+		sort.SliceStable(result, func(i, j int) bool {
+			a, b := result[i], result[j]
+			if a.Goals != b.Goals {
+				return a.Goals > b.Goals
+			}
+			return a.MinutesPlayed < b.MinutesPlayed
+		})
+		result = truncate(result, limit)
+	}
+
 	return result, nil
 }
 
+func (s *reportService) GetTopAssists(limit int, filter ReportFilter) ([]PlayerAssists, error) {
+	// A goal can have two assisters, each joined as a row of their own
+	result := []PlayerAssists{}
+	err := s.goalsInScope(filter).
+		Select(
+			"players.id as player_id," +
+				"COUNT(*) as assists," +
+				"players.name as player_name," +
+				"teams.id as team_id," +
+				"teams.name as team_name").
+		Joins("JOIN players ON players.id = goals.assist_player_id OR players.id = goals.second_assist_player_id").
+		Joins("JOIN teams ON teams.id = goals.team_id").
+		Group("players.id, players.name, teams.id, teams.name").
+		Order("assists DESC, players.name ASC").
+		Limit(sqlLimit(limit, filter)).
+		Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.TieBreak == TieBreakByMinutes {
+		minutes, err := s.minutesPlayed(filter)
+		if err != nil {
+			return nil, err
+		}
+		for i := range result {
+			result[i].MinutesPlayed = minutes[[2]uint{result[i].PlayerID, result[i].TeamID}]
+		}
+		sort.SliceStable(result, func(i, j int) bool {
+			a, b := result[i], result[j]
+			if a.Assists != b.Assists {
+				return a.Assists > b.Assists
+			}
+			return a.MinutesPlayed < b.MinutesPlayed
+		})
+		result = truncate(result, limit)
+	}
+
+	return result, nil
+}
+
+func (s *reportService) GetGoalContributions(limit int, filter ReportFilter) ([]PlayerContributions, error) {
+	// Every goal joins its scorer (unless it is an own goal) and its assisters
+	result := []PlayerContributions{}
+	err := s.goalsInScope(filter).
+		Select(
+			"players.id as player_id,"+
+				"SUM(CASE WHEN players.id = goals.player_id THEN 1 ELSE 0 END) as goals,"+
+				"SUM(CASE WHEN players.id = goals.player_id THEN 0 ELSE 1 END) as assists,"+
+				"COUNT(*) as contributions,"+
+				"players.name as player_name,"+
+				"teams.id as team_id,"+
+				"teams.name as team_name").
+		Joins("JOIN players ON (players.id = goals.player_id AND goals.type <> ?)"+
+			" OR players.id = goals.assist_player_id"+
+			" OR players.id = goals.second_assist_player_id", models.OwnGoal).
+		Joins("JOIN teams ON teams.id = goals.team_id").
+		Group("players.id, players.name, teams.id, teams.name").
+		Order("contributions DESC, goals DESC, players.name ASC").
+		Limit(sqlLimit(limit, filter)).
+		Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.TieBreak == TieBreakByMinutes {
+		minutes, err := s.minutesPlayed(filter)
+		if err != nil {
+			return nil, err
+		}
+		for i := range result {
+			result[i].MinutesPlayed = minutes[[2]uint{result[i].PlayerID, result[i].TeamID}]
+		}
+		sort.SliceStable(result, func(i, j int) bool {
+			a, b := result[i], result[j]
+			if a.Contributions != b.Contributions {
+				return a.Contributions > b.Contributions
+			}
+			if a.Goals != b.Goals {
+				return a.Goals > b.Goals
+			}
+			return a.MinutesPlayed < b.MinutesPlayed
+		})
+		result = truncate(result, limit)
+	}
+
+	return result, nil
+}
+
+// goalsInScope selects the goals of the matches covered by the filter,
+// credited to the filtered team if one is given.
+func (s *reportService) goalsInScope(filter ReportFilter) *gorm.DB {
+	query := s.repo.GetDB().Table("goals").
+		Joins("JOIN matches ON matches.id = goals.match_id").
+		Where("goals.deleted_at IS NULL AND matches.deleted_at IS NULL").
+		Scopes(repositories.ScopeMatches(filter.matchFilter()))
+	if filter.TeamID != nil {
+		query = query.Where("goals.team_id = ?", *filter.TeamID)
+	}
+	return query
+}
+
+// minutesPlayed adds up the minutes each player spent on the pitch for each
+// team in the matches covered by the filter.
+func (s *reportService) minutesPlayed(filter ReportFilter) (map[[2]uint]int, error) {
+	matches, err := s.repo.FindByFilter(filter.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+	lineups, err := s.lineupRepo.FindByFilter(filter.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+	subs, err := s.lineupRepo.FindSubstitutionsByFilter(filter.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+	return minutesPlayed(matches, lineups, subs), nil
+}
+
+// sqlLimit is the row limit of a leaderboard query. Breaking ties by minutes
+// happens after the query, so then every row is fetched.
+func sqlLimit(limit int, filter ReportFilter) int {
+	if filter.TieBreak == TieBreakByMinutes {
+		return -1
+	}
+	return limit
+}
+
+func truncate[T any](rows []T, limit int) []T {
+	if len(rows) > limit {
+		return rows[:limit]
+	}
+	return rows
+}
+
 func (s *reportService) GetMatchReport(matchID uint) (*MatchReport, error) {
 	match, err := s.repo.FindByID(matchID)
 	if err != nil {
 		return nil, err
 	}
 
-	report := &MatchReport{
-		MatchID:   match.ID,
-		HomeTeam:  match.HomeTeam.Name,
-		AwayTeam:  match.AwayTeam.Name,
-		HomeScore: match.HomeScore,
-		AwayScore: match.AwayScore,
-		MatchTime: match.MatchTime.Format(time.RFC3339),
-		Status:    string(match.Status),
+	// Wins are counted within the match's own season and competition, as
+	// in a report scoped to them, so friendlies never count towards a
+	// competitive match
+	scope := ReportFilter{SeasonID: match.SeasonID, CompetitionID: match.CompetitionID}
+	wins, err := s.cumulativeWins(scope.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	report := matchReport(*match, wins)
+	return &report, nil
+}
+
+// GetMatchReports reports on every match in scope, in kick-off order.
+func (s *reportService) GetMatchReports(filter ReportFilter) ([]MatchReport, error) {
+	matches, err := s.repo.FindDetailedByFilter(filter.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+	wins, err := s.cumulativeWins(filter.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]MatchReport, 0, len(matches))
+	for _, match := range matches {
+		reports = append(reports, matchReport(match, wins))
+	}
+	return reports, nil
+}
+
+// cumulativeWins indexes the running win counts by match and team.
+func (s *reportService) cumulativeWins(filter repositories.MatchFilter) (map[[2]uint]int, error) {
+	rows, err := s.repo.FindCumulativeWins(filter)
+	if err != nil {
+		return nil, err
+	}
+	wins := make(map[[2]uint]int, len(rows))
+	for _, row := range rows {
+		wins[[2]uint{row.MatchID, row.TeamID}] = row.Wins
+	}
+	return wins, nil
+}
+
+// matchReport builds the report of a match loaded with its teams and goal
+// scorers.
+func matchReport(match models.Match, wins map[[2]uint]int) MatchReport {
+	report := MatchReport{
+		MatchID:                match.ID,
+		HomeTeam:               match.HomeTeam.Name,
+		AwayTeam:               match.AwayTeam.Name,
+		HomeScore:              match.HomeScore,
+		AwayScore:              match.AwayScore,
+		HomeExtraTimeScore:     match.HomeExtraTimeScore,
+		AwayExtraTimeScore:     match.AwayExtraTimeScore,
+		HomePenalties:          match.HomePenalties,
+		AwayPenalties:          match.AwayPenalties,
+		FinalScore:             "0-0",
+		Result:                 matchResult(match),
+		StatusAkhir:            legacyMatchResult(match),
+		MatchTime:              match.MatchTime.Format(time.RFC3339),
+		Status:                 string(match.Status),
+		HomeWinsUntilThisMatch: wins[[2]uint{match.ID, match.HomeTeamID}],
+		AwayWinsUntilThisMatch: wins[[2]uint{match.ID, match.AwayTeamID}],
+	}
+	if match.HomeScore != nil && match.AwayScore != nil {
+		report.FinalScore = finalScore(match)
 	}
 
 	// Add goals to report
+	scorers := make(map[uint]*PlayerGoals)
 	for _, goal := range match.Goals {
+		teamName := match.HomeTeam.Name
+		if goal.TeamID == match.AwayTeamID {
+			teamName = match.AwayTeam.Name
+		}
+
 		report.Goals = append(report.Goals, Goal{
 			PlayerName: goal.Player.Name,
+			TeamName:   teamName,
+			Type:       string(goal.Type),
 			Minute:     goal.Minute,
+			AddedTime:  goal.AddedTime,
+			IsOwnGoal:  goal.IsOwnGoal(),
 		})
+
+		if goal.IsOwnGoal() {
+			continue
+		}
+		scorer, ok := scorers[goal.PlayerID]
+		if !ok {
+			scorer = &PlayerGoals{PlayerID: goal.PlayerID, PlayerName: goal.Player.Name, TeamID: goal.TeamID, TeamName: teamName}
+			scorers[goal.PlayerID] = scorer
+		}
+		scorer.Goals++
 	}
 
-	return report, nil
+	for _, scorer := range scorers {
+		report.TopScorers = append(report.TopScorers, *scorer)
+	}
+	sort.Slice(report.TopScorers, func(i, j int) bool {
+		a, b := report.TopScorers[i], report.TopScorers[j]
+		if a.Goals != b.Goals {
+			return a.Goals > b.Goals
+		}
+		return a.PlayerName < b.PlayerName
+	})
+	if len(report.TopScorers) > 0 {
+		report.TopScorer = &report.TopScorers[0].PlayerName
+		report.TopScorerPlayer = report.TopScorer
+	}
+
+	return report
+}
+
+// matchResult describes the outcome of a match in words; a tie settled on
+// penalties names the shootout winner.
+func matchResult(match models.Match) string {
+	if !match.Status.HasFinalResult() || match.HomeScore == nil || match.AwayScore == nil {
+		return ResultNotPlayed
+	}
+	switch {
+	case *match.HomeScore > *match.AwayScore:
+		return ResultHomeWin
+	case *match.HomeScore < *match.AwayScore:
+		return ResultAwayWin
+	case match.HomePenalties != nil && match.AwayPenalties != nil && *match.HomePenalties > *match.AwayPenalties:
+		return ResultHomeWinOnPenalties
+	case match.HomePenalties != nil && match.AwayPenalties != nil && *match.HomePenalties < *match.AwayPenalties:
+		return ResultAwayWinOnPenalties
+	}
+	return ResultDraw
+}
+
+// legacyMatchResult describes the outcome of a match as the legacy server did.
+func legacyMatchResult(match models.Match) string {
+	if !match.Status.HasFinalResult() || match.HomeScore == nil || match.AwayScore == nil {
+		return LegacyResultScheduled
+	}
+	switch {
+	case *match.HomeScore > *match.AwayScore:
+		return LegacyResultHomeWin
+	case *match.HomeScore < *match.AwayScore:
+		return LegacyResultAwayWin
+	}
+	return LegacyResultDraw
+}
+
+func (s *reportService) GetHeadToHead(teamA, teamB uint, limit int, filter ReportFilter) (*HeadToHead, error) {
+	a, err := s.teamRepo.FindByID(teamA)
+	if err != nil {
+		return nil, fmt.Errorf("team %d not found", teamA)
+	}
+	b, err := s.teamRepo.FindByID(teamB)
+	if err != nil {
+		return nil, fmt.Errorf("team %d not found", teamB)
+	}
+
+	matches, err := s.repo.FindBetweenTeams(teamA, teamB, filter.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	h2h := &HeadToHead{
+		TeamA:      HeadToHeadSide{TeamID: a.ID, TeamName: a.Name},
+		TeamB:      HeadToHeadSide{TeamID: b.ID, TeamName: b.Name},
+		TopScorers: []PlayerGoals{},
+		Matches:    []HeadToHeadMeeting{},
+	}
+
+	for _, side := range []*HeadToHeadSide{&h2h.TeamA, &h2h.TeamB} {
+		for _, r := range teamResults(matches, side.TeamID) {
+			side.Overall.add(r)
+			if r.home {
+				side.Home.add(r)
+			} else {
+				side.Away.add(r)
+			}
+		}
+	}
+	h2h.Meetings = h2h.TeamA.Overall.Played
+	h2h.Draws = h2h.TeamA.Overall.Drawn
+
+	scorers := make(map[[2]uint]*PlayerGoals)
+	for _, r := range teamResults(matches, teamA) {
+		match := r.match
+		meeting := HeadToHeadMeeting{
+			MatchID:   match.ID,
+			MatchTime: match.MatchTime.Format(time.RFC3339),
+			HomeTeam:  match.HomeTeam.Name,
+			AwayTeam:  match.AwayTeam.Name,
+			Score:     finalScore(match),
+		}
+		if match.Competition != nil {
+			meeting.Competition = match.Competition.Name
+		}
+		switch r.outcome {
+		case outcomeWin:
+			meeting.WinnerTeamID = &h2h.TeamA.TeamID
+		case outcomeLoss:
+			meeting.WinnerTeamID = &h2h.TeamB.TeamID
+		}
+		h2h.Matches = append(h2h.Matches, meeting)
+
+		for _, goal := range match.Goals {
+			if goal.IsOwnGoal() {
+				continue
+			}
+			key := [2]uint{goal.PlayerID, goal.TeamID}
+			scorer, ok := scorers[key]
+			if !ok {
+				teamName := a.Name
+				if goal.TeamID == b.ID {
+					teamName = b.Name
+				}
+				scorer = &PlayerGoals{PlayerID: goal.PlayerID, PlayerName: goal.Player.Name, TeamID: goal.TeamID, TeamName: teamName}
+				scorers[key] = scorer
+			}
+			scorer.Goals++
+		}
+	}
+	if len(h2h.Matches) > 0 {
+		last := h2h.Matches[len(h2h.Matches)-1]
+		h2h.LastMeeting = &last
+	}
+
+	for _, scorer := range scorers {
+		h2h.TopScorers = append(h2h.TopScorers, *scorer)
+	}
+	sort.Slice(h2h.TopScorers, func(i, j int) bool {
+		x, y := h2h.TopScorers[i], h2h.TopScorers[j]
+		if x.Goals != y.Goals {
+			return x.Goals > y.Goals
+		}
+		if x.PlayerName != y.PlayerName {
+			return x.PlayerName < y.PlayerName
+		}
+		return x.TeamName < y.TeamName
+	})
+	h2h.TopScorers = truncate(h2h.TopScorers, limit)
+
+	return h2h, nil
 }
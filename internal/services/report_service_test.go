@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+
+	"xyz-football/internal/models"
+)
+
+func TestMatchReportLegacyFields(t *testing.T) {
+	scheduled := models.Match{ID: 1, HomeTeamID: 1, AwayTeamID: 2, Status: models.Scheduled}
+	shootout := finishedMatch(1, 1, 2, 1, 1)
+	shootout.HomePenalties, shootout.AwayPenalties = intPtr(5), intPtr(4)
+
+	tests := []struct {
+		name            string
+		match           models.Match
+		wantResult      string
+		wantStatusAkhir string
+	}{
+		{"not played", scheduled, ResultNotPlayed, LegacyResultScheduled},
+		{"home win", finishedMatch(1, 1, 2, 2, 1), ResultHomeWin, LegacyResultHomeWin},
+		{"away win", finishedMatch(1, 1, 2, 0, 3), ResultAwayWin, LegacyResultAwayWin},
+		{"draw", finishedMatch(1, 1, 2, 1, 1), ResultDraw, LegacyResultDraw},
+		{"won on penalties", shootout, ResultHomeWinOnPenalties, LegacyResultDraw},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := matchReport(tt.match, nil)
+			if report.Result != tt.wantResult || report.StatusAkhir != tt.wantStatusAkhir {
+				t.Errorf("result %q, status_akhir %q, want %q, %q",
+					report.Result, report.StatusAkhir, tt.wantResult, tt.wantStatusAkhir)
+			}
+		})
+	}
+
+	t.Run("top scorer", func(t *testing.T) {
+		match := finishedMatch(1, 1, 2, 2, 0)
+		match.HomeTeam.Name, match.AwayTeam.Name = "Alpha", "Beta"
+		match.Goals = []models.Goal{
+			{PlayerID: 7, TeamID: 1, Type: models.OpenPlay, Player: models.Player{Name: "Ann"}},
+			{PlayerID: 7, TeamID: 1, Type: models.OpenPlay, Player: models.Player{Name: "Ann"}},
+		}
+
+		report := matchReport(match, nil)
+		if report.TopScorer == nil || report.TopScorerPlayer == nil || *report.TopScorerPlayer != "Ann" {
+			t.Errorf("top_scorer %v, pencetak_gol_terbanyak %v, want Ann for both", report.TopScorer, report.TopScorerPlayer)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"xyz-football/internal/repositories"
+	"xyz-football/pkg/utils"
+)
+
+// Scores of the ways a result can match a search, best first.
+const (
+	scoreExactName   = 100
+	scoreNumber      = 90
+	scoreNamePrefix  = 80
+	scoreWordPrefix  = 70
+	scoreNameContent = 60
+	scoreCity        = 40
+	scoreStadium     = 30
+	scoreOther       = 10
+
+	// Candidates fetched per kind before ranking
+	searchCandidates = 200
+)
+
+type SearchService interface {
+	Search(q string, limit int) ([]SearchResult, error)
+}
+
+type searchService struct {
+	teamRepo   repositories.TeamRepository
+	playerRepo repositories.PlayerRepository
+}
+
+// SearchResult is a team or player found by a search. MatchedOn names the
+// field that earned the score.
+type SearchResult struct {
+	Type      string `json:"type"` // "team" or "player"
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Detail    string `json:"detail,omitempty"` // city of a team, team of a player
+	Number    int    `json:"number,omitempty"`
+	MatchedOn string `json:"matched_on"`
+	Score     int    `json:"score"`
+}
+
+func NewSearchService(teamRepo repositories.TeamRepository, playerRepo repositories.PlayerRepository) SearchService {
+	return &searchService{teamRepo: teamRepo, playerRepo: playerRepo}
+}
+
+// Search looks for teams by name, city and stadium and for players by name
+// and shirt number, ignoring case and accents. Every word of the query has
+// to match; results are ranked by how well they match, then by name.
+func (s *searchService) Search(q string, limit int) ([]SearchResult, error) {
+	key := utils.Fold(q)
+
+	// A shirt number may be a single digit; text needs a little more
+	var number *int
+	if n, err := strconv.Atoi(key); err == nil {
+		number = &n
+	} else if len([]rune(key)) < 2 {
+		return nil, errors.New("search query must be at least 2 characters")
+	}
+	terms := strings.Fields(key)
+
+	teams, err := s.teamRepo.Search(terms, searchCandidates)
+	if err != nil {
+		return nil, err
+	}
+	players, err := s.playerRepo.Search(terms, number, searchCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(teams)+len(players))
+	for _, team := range teams {
+		result := SearchResult{Type: "team", ID: team.ID, Name: team.Name, Detail: team.City}
+		result.Score, result.MatchedOn = nameScore(team.Name, key)
+		if result.Score == 0 {
+			switch {
+			case strings.Contains(utils.Fold(team.City), key):
+				result.Score, result.MatchedOn = scoreCity, "city"
+			case strings.Contains(utils.Fold(team.StadiumAddr), key):
+				result.Score, result.MatchedOn = scoreStadium, "stadium"
+			default:
+				// The words are spread over several fields
+				result.Score, result.MatchedOn = scoreOther, "team"
+			}
+		}
+		results = append(results, result)
+	}
+	for _, player := range players {
+		result := SearchResult{Type: "player", ID: player.ID, Name: player.Name, Detail: player.Team.Name, Number: player.Number}
+		result.Score, result.MatchedOn = nameScore(player.Name, key)
+		if number != nil && player.Number == *number && result.Score < scoreNumber {
+			result.Score, result.MatchedOn = scoreNumber, "number"
+		}
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Type > b.Type // teams before players
+	})
+	return truncate(results, limit), nil
+}
+
+// nameScore rates how well a name matches a folded query, 0 when the query
+// is not part of the name.
+func nameScore(name, key string) (int, string) {
+	folded := utils.Fold(name)
+	switch {
+	case folded == key:
+		return scoreExactName, "name"
+	case strings.HasPrefix(folded, key):
+		return scoreNamePrefix, "name"
+	case strings.Contains(" "+folded, " "+key):
+		return scoreWordPrefix, "name"
+	case containsAll(folded, strings.Fields(key)):
+		return scoreNameContent, "name"
+	}
+	return 0, ""
+}
+
+func containsAll(text string, terms []string) bool {
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
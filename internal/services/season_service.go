@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
+)
+
+type SeasonService interface {
+	CreateSeason(season *models.Season) error
+	GetAllSeasons() ([]models.Season, error)
+	GetSeasonByID(id uint) (*models.Season, error)
+	UpdateSeason(season *models.Season) error
+	DeleteSeason(id uint) error
+}
+
+type seasonService struct {
+	repo repositories.SeasonRepository
+}
+
+func NewSeasonService(repo repositories.SeasonRepository) SeasonService {
+	return &seasonService{repo: repo}
+}
+
+func (s *seasonService) CreateSeason(season *models.Season) error {
+	if !season.EndDate.After(season.StartDate) {
+		return errors.New("season end date must be after start date")
+	}
+
+	return s.repo.Create(season)
+}
+
+func (s *seasonService) GetAllSeasons() ([]models.Season, error) {
+	return s.repo.FindAll()
+}
+
+func (s *seasonService) GetSeasonByID(id uint) (*models.Season, error) {
+	return s.repo.FindByID(id)
+}
+
+func (s *seasonService) UpdateSeason(season *models.Season) error {
+	if !season.EndDate.After(season.StartDate) {
+		return errors.New("season end date must be after start date")
+	}
+
+	return s.repo.Update(season)
+}
+
+func (s *seasonService) DeleteSeason(id uint) error {
+	// Check if season exists
+	if _, err := s.repo.FindByID(id); err != nil {
+		return errors.New("season not found")
+	}
+
+	return s.repo.Delete(id)
+}
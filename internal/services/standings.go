@@ -0,0 +1,179 @@
+package services
+
+import (
+	"sort"
+
+	"xyz-football/internal/models"
+)
+
+// Tie-breaker labels that are not configurable rules but still show up on a
+// standings row.
+const (
+	tieBreakPoints     models.TieBreaker = "points"
+	tieBreakUnresolved models.TieBreaker = "unresolved"
+)
+
+// selection picks, per team, the IDs of the matches that count towards its
+// row. A nil selection counts every match for both teams.
+type selection map[uint]map[uint]bool
+
+func (sel selection) counts(teamID, matchID uint) bool {
+	return sel == nil || sel[teamID][matchID]
+}
+
+// applySide adds one finished match to the row of one of its teams.
+func applySide(row *TeamStanding, match models.Match, home bool, rules models.StandingsRules) {
+	teamID, scored, conceded := match.HomeTeamID, *match.HomeScore, *match.AwayScore
+	if !home {
+		teamID, scored, conceded = match.AwayTeamID, conceded, scored
+		row.AwayGoalsFor += scored
+	}
+
+	row.Played++
+	row.GoalsFor += scored
+	row.GoalsAway += conceded
+	row.GoalDifference = row.GoalsFor - row.GoalsAway
+
+	switch {
+	case scored > conceded:
+		row.Won++
+		row.Points += rules.PointsForWin
+	case scored < conceded:
+		row.Lost++
+		row.Points += rules.PointsForLoss
+	default:
+		row.Drawn++
+		row.Points += rules.PointsForDraw
+	}
+
+	for _, card := range match.Cards {
+		if card.TeamID == teamID {
+			row.FairPlayPoints -= card.Type.FairPlayPoints()
+		}
+	}
+}
+
+// rankStandings orders a table by points and then by the configured
+// tie-breakers. matches and sel are the results the table was built from and
+// are needed for the head-to-head criteria. Each row records the tie-breaker
+// that settled its place among the teams it was level on points with.
+func rankStandings(rows []TeamStanding, matches []models.Match, sel selection, rules models.StandingsRules) []TeamStanding {
+	// Alphabetical base order keeps unresolved ties stable between requests
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].TeamName < rows[j].TeamName
+	})
+
+	criteria := append([]models.TieBreaker{tieBreakPoints}, rules.TieBreakers...)
+	ranked := rankGroup(rows, criteria, matches, sel, rules)
+
+	for i := range ranked {
+		ranked[i].Position = i + 1
+	}
+	return ranked
+}
+
+// rankGroup sorts a group of teams by the first criterion, then recursively
+// ranks every run of teams that are still level with the remaining criteria.
+func rankGroup(group []TeamStanding, criteria []models.TieBreaker, matches []models.Match, sel selection, rules models.StandingsRules) []TeamStanding {
+	if len(group) < 2 {
+		return group
+	}
+	if len(criteria) == 0 {
+		for i := range group {
+			group[i].TieBreaker = string(tieBreakUnresolved)
+		}
+		return group
+	}
+
+	criterion := criteria[0]
+	values := criterionValues(criterion, group, matches, sel, rules)
+	sort.SliceStable(group, func(i, j int) bool {
+		return values[group[i].TeamID] > values[group[j].TeamID]
+	})
+
+	ranked := make([]TeamStanding, 0, len(group))
+	for i := 0; i < len(group); {
+		j := i + 1
+		for j < len(group) && values[group[j].TeamID] == values[group[i].TeamID] {
+			j++
+		}
+
+		run := group[i:j]
+		if len(run) == 1 && criterion != tieBreakPoints {
+			run[0].TieBreaker = string(criterion)
+		}
+		ranked = append(ranked, rankGroup(run, criteria[1:], matches, sel, rules)...)
+		i = j
+	}
+
+	return ranked
+}
+
+// criterionValues scores every team in the group for one criterion; higher is better.
+func criterionValues(criterion models.TieBreaker, group []TeamStanding, matches []models.Match, sel selection, rules models.StandingsRules) map[uint]int {
+	values := make(map[uint]int, len(group))
+
+	switch criterion {
+	case models.TieBreakHeadToHeadPoints,
+		models.TieBreakHeadToHeadGoalDifference,
+		models.TieBreakHeadToHeadGoalsFor,
+		models.TieBreakHeadToHeadAwayGoals:
+		mini := headToHeadTable(group, matches, sel, rules)
+		for _, row := range group {
+			h2h := mini[row.TeamID]
+			switch criterion {
+			case models.TieBreakHeadToHeadPoints:
+				values[row.TeamID] = h2h.Points
+			case models.TieBreakHeadToHeadGoalDifference:
+				values[row.TeamID] = h2h.GoalDifference
+			case models.TieBreakHeadToHeadGoalsFor:
+				values[row.TeamID] = h2h.GoalsFor
+			case models.TieBreakHeadToHeadAwayGoals:
+				values[row.TeamID] = h2h.AwayGoalsFor
+			}
+		}
+		return values
+	}
+
+	for _, row := range group {
+		switch criterion {
+		case tieBreakPoints:
+			values[row.TeamID] = row.Points
+		case models.TieBreakGoalDifference:
+			values[row.TeamID] = row.GoalDifference
+		case models.TieBreakGoalsFor:
+			values[row.TeamID] = row.GoalsFor
+		case models.TieBreakAwayGoalsFor:
+			values[row.TeamID] = row.AwayGoalsFor
+		case models.TieBreakWins:
+			values[row.TeamID] = row.Won
+		case models.TieBreakFairPlay:
+			values[row.TeamID] = row.FairPlayPoints
+		}
+	}
+	return values
+}
+
+// headToHeadTable builds a mini-table from the matches played between the
+// teams of the group only.
+func headToHeadTable(group []TeamStanding, matches []models.Match, sel selection, rules models.StandingsRules) map[uint]*TeamStanding {
+	mini := make(map[uint]*TeamStanding, len(group))
+	for _, row := range group {
+		mini[row.TeamID] = &TeamStanding{TeamID: row.TeamID}
+	}
+
+	for _, match := range matches {
+		home, away := mini[match.HomeTeamID], mini[match.AwayTeamID]
+		if home == nil || away == nil {
+			continue
+		}
+		if sel.counts(match.HomeTeamID, match.ID) {
+			applySide(home, match, true, rules)
+		}
+		if sel.counts(match.AwayTeamID, match.ID) {
+			applySide(away, match, false, rules)
+		}
+	}
+
+	return mini
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"testing"
+
+	"xyz-football/internal/models"
+)
+
+func intPtr(v int) *int { return &v }
+
+func finishedMatch(id, home, away uint, homeScore, awayScore int) models.Match {
+	return models.Match{
+		ID:         id,
+		HomeTeamID: home,
+		AwayTeamID: away,
+		HomeScore:  intPtr(homeScore),
+		AwayScore:  intPtr(awayScore),
+		Status:     models.Finished,
+	}
+}
+
+func TestRankStandings(t *testing.T) {
+	type placed struct {
+		teamID     uint
+		tieBreaker string
+	}
+
+	tests := []struct {
+		name    string
+		rows    []TeamStanding
+		matches []models.Match
+		sel     selection
+		rules   models.StandingsRules
+		want    []placed
+	}{
+		{
+			name: "points only",
+			rows: []TeamStanding{
+				{TeamID: 1, TeamName: "Alpha", Points: 3},
+				{TeamID: 2, TeamName: "Beta", Points: 9},
+				{TeamID: 3, TeamName: "Gamma", Points: 6},
+			},
+			rules: models.DefaultStandingsRules(),
+			want:  []placed{{2, ""}, {3, ""}, {1, ""}},
+		},
+		{
+			name: "goal difference settles a tie on points",
+			rows: []TeamStanding{
+				{TeamID: 1, TeamName: "Alpha", Points: 6, GoalDifference: 1},
+				{TeamID: 2, TeamName: "Beta", Points: 6, GoalDifference: 4},
+				{TeamID: 3, TeamName: "Gamma", Points: 9},
+			},
+			rules: models.DefaultStandingsRules(),
+			want:  []placed{{3, ""}, {2, "goal_difference"}, {1, "goal_difference"}},
+		},
+		{
+			name: "goals scored after level goal difference",
+			rows: []TeamStanding{
+				{TeamID: 1, TeamName: "Alpha", Points: 6, GoalDifference: 2, GoalsFor: 5},
+				{TeamID: 2, TeamName: "Beta", Points: 6, GoalDifference: 2, GoalsFor: 8},
+			},
+			rules: models.DefaultStandingsRules(),
+			want:  []placed{{2, "goals_for"}, {1, "goals_for"}},
+		},
+		{
+			name: "unresolved ties stay in alphabetical order",
+			rows: []TeamStanding{
+				{TeamID: 1, TeamName: "Gamma", Points: 4, GoalDifference: 1, GoalsFor: 3},
+				{TeamID: 2, TeamName: "Alpha", Points: 4, GoalDifference: 1, GoalsFor: 3},
+				{TeamID: 3, TeamName: "Beta", Points: 7},
+			},
+			rules: models.DefaultStandingsRules(),
+			want:  []placed{{3, ""}, {2, "unresolved"}, {1, "unresolved"}},
+		},
+		{
+			name: "head to head before goal difference",
+			rows: []TeamStanding{
+				{TeamID: 1, TeamName: "Alpha", Points: 6, GoalDifference: 5},
+				{TeamID: 2, TeamName: "Beta", Points: 6, GoalDifference: 1},
+			},
+			matches: []models.Match{
+				finishedMatch(1, 1, 2, 0, 1),
+				finishedMatch(2, 1, 3, 6, 0),
+			},
+			rules: models.StandingsRules{
+				PointsForWin: 3,
+				TieBreakers:  []models.TieBreaker{models.TieBreakHeadToHeadPoints, models.TieBreakGoalDifference},
+			},
+			want: []placed{{2, "head_to_head_points"}, {1, "head_to_head_points"}},
+		},
+		{
+			name: "head to head level falls through to the next criterion",
+			rows: []TeamStanding{
+				{TeamID: 1, TeamName: "Alpha", Points: 4, GoalDifference: 3},
+				{TeamID: 2, TeamName: "Beta", Points: 4, GoalDifference: 1},
+			},
+			matches: []models.Match{
+				finishedMatch(1, 1, 2, 1, 1),
+			},
+			rules: models.StandingsRules{
+				PointsForWin:  3,
+				PointsForDraw: 1,
+				TieBreakers:   []models.TieBreaker{models.TieBreakHeadToHeadPoints, models.TieBreakGoalDifference},
+			},
+			want: []placed{{1, "goal_difference"}, {2, "goal_difference"}},
+		},
+		{
+			name: "head to head only counts selected matches",
+			rows: []TeamStanding{
+				{TeamID: 1, TeamName: "Alpha", Points: 3},
+				{TeamID: 2, TeamName: "Beta", Points: 3},
+			},
+			matches: []models.Match{
+				finishedMatch(1, 1, 2, 0, 2),
+				finishedMatch(2, 2, 1, 0, 1),
+			},
+			sel: selection{1: {2: true}, 2: {2: true}},
+			rules: models.StandingsRules{
+				PointsForWin: 3,
+				TieBreakers:  []models.TieBreaker{models.TieBreakHeadToHeadPoints},
+			},
+			want: []placed{{1, "head_to_head_points"}, {2, "head_to_head_points"}},
+		},
+		{
+			name: "fair play prefers fewer bookings",
+			rows: []TeamStanding{
+				{TeamID: 1, TeamName: "Alpha", Points: 5, FairPlayPoints: -4},
+				{TeamID: 2, TeamName: "Beta", Points: 5, FairPlayPoints: -1},
+			},
+			rules: models.StandingsRules{
+				PointsForWin: 3,
+				TieBreakers:  []models.TieBreaker{models.TieBreakFairPlay},
+			},
+			want: []placed{{2, "fair_play"}, {1, "fair_play"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranked := rankStandings(tt.rows, tt.matches, tt.sel, tt.rules)
+			if len(ranked) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d", len(ranked), len(tt.want))
+			}
+			for i, want := range tt.want {
+				row := ranked[i]
+				if row.Position != i+1 || row.TeamID != want.teamID || row.TieBreaker != want.tieBreaker {
+					t.Errorf("row %d = position %d team %d tie-breaker %q, want position %d team %d tie-breaker %q",
+						i, row.Position, row.TeamID, row.TieBreaker, i+1, want.teamID, want.tieBreaker)
+				}
+			}
+		})
+	}
+}
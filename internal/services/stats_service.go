@@ -0,0 +1,368 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
+)
+
+type StatsService interface {
+	GetPlayerStats(playerID uint, filter ReportFilter) (*PlayerStats, error)
+	GetTeamStats(teamID uint, filter ReportFilter, formLength int) (*TeamStats, error)
+}
+
+type statsService struct {
+	matchRepo    repositories.MatchRepository
+	goalRepo     repositories.GoalRepository
+	lineupRepo   repositories.LineupRepository
+	playerRepo   repositories.PlayerRepository
+	contractRepo repositories.ContractRepository
+	teamRepo     repositories.TeamRepository
+}
+
+// PlayerStats is a player's record over the matches in scope. Goals leave own
+// goals out; they are only counted in OwnGoals and GoalsByType. Minutes come
+// from the recorded lineups, so GoalsPer90 only counts the goals of matches
+// with a lineup and is left out until there is one.
+type PlayerStats struct {
+	PlayerID      uint               `json:"player_id"`
+	PlayerName    string             `json:"player_name"`
+	TeamName      string             `json:"team_name"`
+	Appearances   int                `json:"appearances"`
+	Starts        int                `json:"starts"`
+	MinutesPlayed int                `json:"minutes_played"`
+	Goals         int                `json:"goals"`
+	OwnGoals      int                `json:"own_goals"`
+	Assists       int                `json:"assists"`
+	GoalsPer90    *float64           `json:"goals_per_90,omitempty"`
+	Yellows       int                `json:"yellows"`
+	SecondYellows int                `json:"second_yellows"`
+	Reds          int                `json:"reds"`
+	GoalsByType   map[string]int     `json:"goals_by_type"`
+	Matches       []PlayerMatchStats `json:"matches"`
+}
+
+// PlayerMatchStats is one match the player took part in. Started and
+// MinutesPlayed are only known when the team's lineup was recorded.
+type PlayerMatchStats struct {
+	MatchID       uint         `json:"match_id"`
+	MatchTime     string       `json:"match_time"`
+	TeamName      string       `json:"team_name"`
+	Opponent      string       `json:"opponent"`
+	Home          bool         `json:"home"`
+	Score         string       `json:"score"`
+	Started       bool         `json:"started"`
+	MinutesPlayed int          `json:"minutes_played"`
+	Goals         []PlayerGoal `json:"goals"`
+	Assists       int          `json:"assists"`
+	Cards         []string     `json:"cards"`
+}
+
+type PlayerGoal struct {
+	Type      string `json:"type"`
+	Minute    int    `json:"minute"`
+	AddedTime int    `json:"added_time,omitempty"`
+}
+
+// TeamStats is a team's record over the matches in scope, overall and split
+// by venue. Form lists the last results, oldest first, and CurrentStreak the
+// run the team is on, e.g. "W3".
+type TeamStats struct {
+	TeamID                uint             `json:"team_id"`
+	TeamName              string           `json:"team_name"`
+	Overall               TeamRecord       `json:"overall"`
+	Home                  TeamRecord       `json:"home"`
+	Away                  TeamRecord       `json:"away"`
+	BiggestWin            *TeamMatchResult `json:"biggest_win,omitempty"`
+	BiggestLoss           *TeamMatchResult `json:"biggest_loss,omitempty"`
+	Form                  string           `json:"form"`
+	CurrentStreak         string           `json:"current_streak"`
+	LongestWinningStreak  int              `json:"longest_winning_streak"`
+	LongestUnbeatenStreak int              `json:"longest_unbeaten_streak"`
+	LongestLosingStreak   int              `json:"longest_losing_streak"`
+}
+
+type TeamMatchResult struct {
+	MatchID   uint   `json:"match_id"`
+	MatchTime string `json:"match_time"`
+	Opponent  string `json:"opponent"`
+	Home      bool   `json:"home"`
+	Score     string `json:"score"`
+}
+
+func newTeamMatchResult(result teamResult) *TeamMatchResult {
+	opponent := result.match.AwayTeam.Name
+	if !result.home {
+		opponent = result.match.HomeTeam.Name
+	}
+	return &TeamMatchResult{
+		MatchID:   result.match.ID,
+		MatchTime: result.match.MatchTime.Format(time.RFC3339),
+		Opponent:  opponent,
+		Home:      result.home,
+		Score:     finalScore(result.match),
+	}
+}
+
+func NewStatsService(
+	matchRepo repositories.MatchRepository,
+	goalRepo repositories.GoalRepository,
+	lineupRepo repositories.LineupRepository,
+	playerRepo repositories.PlayerRepository,
+	contractRepo repositories.ContractRepository,
+	teamRepo repositories.TeamRepository,
+) StatsService {
+	return &statsService{
+		matchRepo:    matchRepo,
+		goalRepo:     goalRepo,
+		lineupRepo:   lineupRepo,
+		playerRepo:   playerRepo,
+		contractRepo: contractRepo,
+		teamRepo:     teamRepo,
+	}
+}
+
+// GetPlayerStats builds a player's record from the finished and awarded
+// matches covered by the filter. With a team filter only the matches the
+// player played for that team are counted.
+func (s *statsService) GetPlayerStats(playerID uint, filter ReportFilter) (*PlayerStats, error) {
+	player, err := s.playerRepo.FindByID(playerID)
+	if err != nil {
+		return nil, errors.New("player not found")
+	}
+
+	contracts, err := s.contractRepo.FindByPlayer(playerID)
+	if err != nil {
+		return nil, err
+	}
+	goals, err := s.goalRepo.FindByPlayer(playerID, filter.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+	assists, err := s.goalRepo.FindAssistsByPlayer(playerID, filter.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+	matches, err := s.matchRepo.FindByFilter(filter.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+	lineups, err := s.lineupRepo.FindByFilter(filter.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+	subs, err := s.lineupRepo.FindSubstitutionsByFilter(filter.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+	onPitch := matchAppearances(matches, lineups, subs)
+
+	stats := &PlayerStats{
+		PlayerID:    player.ID,
+		PlayerName:  player.Name,
+		TeamName:    player.Team.Name,
+		GoalsByType: make(map[string]int),
+		Matches:     []PlayerMatchStats{},
+	}
+
+	goalsByMatch := make(map[uint][]models.Goal)
+	for _, goal := range goals {
+		goalsByMatch[goal.MatchID] = append(goalsByMatch[goal.MatchID], goal)
+	}
+	timedGoals := 0 // goals in matches whose minutes are known
+	assistsByMatch := make(map[uint]int)
+	for _, goal := range assists {
+		assistsByMatch[goal.MatchID]++
+	}
+
+	for _, match := range matches {
+		if !match.Status.HasFinalResult() || match.HomeScore == nil || match.AwayScore == nil {
+			continue
+		}
+
+		teamID := teamAt(contracts, match.MatchTime)
+		if teamID != match.HomeTeamID && teamID != match.AwayTeamID {
+			continue
+		}
+		if filter.TeamID != nil && teamID != *filter.TeamID {
+			continue
+		}
+
+		var cards []string
+		for _, card := range match.Cards {
+			if card.PlayerID == playerID {
+				cards = append(cards, string(card.Type))
+			}
+		}
+
+		a, played := onPitch[match.ID][playerID]
+		matchGoals, matchAssists := goalsByMatch[match.ID], assistsByMatch[match.ID]
+		involved := played || len(matchGoals) > 0 || matchAssists > 0
+		if !involved && len(cards) == 0 {
+			// Not involved, or an unused substitute
+			continue
+		}
+
+		item := PlayerMatchStats{
+			MatchID:   match.ID,
+			MatchTime: match.MatchTime.Format(time.RFC3339),
+			TeamName:  match.HomeTeam.Name,
+			Opponent:  match.AwayTeam.Name,
+			Home:      teamID == match.HomeTeamID,
+			Score:     finalScore(match),
+			Goals:     []PlayerGoal{},
+			Assists:   matchAssists,
+			Cards:     []string{},
+		}
+		if !item.Home {
+			item.TeamName, item.Opponent = item.Opponent, item.TeamName
+		}
+		if played {
+			item.Started = a.on == 0
+			item.MinutesPlayed = a.minutes()
+		}
+		if cards != nil {
+			item.Cards = cards
+		}
+
+		for _, goal := range matchGoals {
+			item.Goals = append(item.Goals, PlayerGoal{
+				Type:      string(goal.Type),
+				Minute:    goal.Minute,
+				AddedTime: goal.AddedTime,
+			})
+			stats.GoalsByType[string(goal.Type)]++
+			switch {
+			case goal.IsOwnGoal():
+				stats.OwnGoals++
+			case played:
+				stats.Goals++
+				timedGoals++
+			default:
+				stats.Goals++
+			}
+		}
+		for _, card := range cards {
+			switch models.CardType(card) {
+			case models.YellowCard:
+				stats.Yellows++
+			case models.SecondYellowCard:
+				stats.SecondYellows++
+			case models.RedCard:
+				stats.Reds++
+			}
+		}
+
+		// A booking alone, e.g. on the bench, counts towards the cards but
+		// is no appearance
+		if involved {
+			stats.Appearances++
+		}
+		if item.Started {
+			stats.Starts++
+		}
+		stats.MinutesPlayed += item.MinutesPlayed
+		stats.Assists += matchAssists
+		stats.Matches = append(stats.Matches, item)
+	}
+
+	if stats.MinutesPlayed > 0 {
+		per90 := math.Round(float64(timedGoals)*90/float64(stats.MinutesPlayed)*100) / 100
+		stats.GoalsPer90 = &per90
+	}
+
+	return stats, nil
+}
+
+// GetTeamStats builds a team's record from the finished and awarded matches
+// covered by the filter. formLength defaults to 5.
+func (s *statsService) GetTeamStats(teamID uint, filter ReportFilter, formLength int) (*TeamStats, error) {
+	team, err := s.teamRepo.FindByID(teamID)
+	if err != nil {
+		return nil, errors.New("team not found")
+	}
+	if formLength <= 0 {
+		formLength = defaultFormLength
+	}
+
+	matches, err := s.matchRepo.FindByFilter(filter.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+	results := teamResults(matches, teamID)
+
+	stats := &TeamStats{
+		TeamID:        team.ID,
+		TeamName:      team.Name,
+		Form:          formString(results, formLength),
+		CurrentStreak: currentStreak(results),
+		LongestWinningStreak: longestRun(results, func(r teamResult) bool {
+			return r.outcome == outcomeWin
+		}),
+		LongestUnbeatenStreak: longestRun(results, func(r teamResult) bool {
+			return r.outcome != outcomeLoss
+		}),
+		LongestLosingStreak: longestRun(results, func(r teamResult) bool {
+			return r.outcome == outcomeLoss
+		}),
+	}
+
+	// The widest margin wins; among equal margins the one with more goals,
+	// then the earliest
+	var biggestWin, biggestLoss *teamResult
+	for i, r := range results {
+		stats.Overall.add(r)
+		if r.home {
+			stats.Home.add(r)
+		} else {
+			stats.Away.add(r)
+		}
+
+		switch {
+		case r.outcome == outcomeWin && (biggestWin == nil ||
+			r.margin() > biggestWin.margin() ||
+			r.margin() == biggestWin.margin() && r.goalsFor > biggestWin.goalsFor):
+			biggestWin = &results[i]
+		case r.outcome == outcomeLoss && (biggestLoss == nil ||
+			r.margin() < biggestLoss.margin() ||
+			r.margin() == biggestLoss.margin() && r.goalsAgainst > biggestLoss.goalsAgainst):
+			biggestLoss = &results[i]
+		}
+	}
+	if biggestWin != nil {
+		stats.BiggestWin = newTeamMatchResult(*biggestWin)
+	}
+	if biggestLoss != nil {
+		stats.BiggestLoss = newTeamMatchResult(*biggestLoss)
+	}
+
+	return stats, nil
+}
+
+// teamAt returns the team a player was with at the given time, 0 when no
+// contract covers it.
+func teamAt(contracts []models.PlayerContract, at time.Time) uint {
+	for _, contract := range contracts {
+		if contract.ActiveAt(at) {
+			return contract.TeamID
+		}
+	}
+	return 0
+}
+
+// finalScore formats the result, e.g. "2-1", marking extra time and adding
+// the shootout when there was one, e.g. "1-1 aet (4-3 pens)".
+func finalScore(match models.Match) string {
+	score := fmt.Sprintf("%d-%d", *match.HomeScore, *match.AwayScore)
+	if match.HomeExtraTimeScore != nil && match.AwayExtraTimeScore != nil {
+		score += " aet"
+	}
+	if match.HomePenalties != nil && match.AwayPenalties != nil {
+		score += fmt.Sprintf(" (%d-%d pens)", *match.HomePenalties, *match.AwayPenalties)
+	}
+	return score
+}
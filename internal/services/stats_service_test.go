@@ -0,0 +1,66 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"xyz-football/internal/models"
+	"xyz-football/internal/repositories"
+)
+
+func TestStatsServiceGetTeamStatsForm(t *testing.T) {
+	db := newTestDB(t)
+	team, opponent := &models.Team{Name: "Alpha"}, &models.Team{Name: "Beta"}
+	if err := db.Create(team).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(opponent).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// W W D L W W L, alternating home and away
+	scores := [][2]int{{2, 0}, {0, 1}, {1, 1}, {3, 0}, {1, 0}, {2, 4}, {0, 2}}
+	kickOff := time.Date(2025, 8, 1, 15, 0, 0, 0, time.UTC)
+	for i, score := range scores {
+		match := finishedMatch(0, team.ID, opponent.ID, score[0], score[1])
+		if i%2 == 1 {
+			match.HomeTeamID, match.AwayTeamID = opponent.ID, team.ID
+		}
+		match.MatchTime = kickOff.AddDate(0, 0, 7*i)
+		if err := db.Create(&match).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := NewStatsService(
+		repositories.NewMatchRepository(db),
+		repositories.NewGoalRepository(db),
+		repositories.NewLineupRepository(db),
+		repositories.NewPlayerRepository(db),
+		repositories.NewContractRepository(db),
+		repositories.NewTeamRepository(db),
+	)
+
+	tests := []struct {
+		name       string
+		formLength int
+		want       string
+	}{
+		{"default length", 0, "DLWWL"},
+		{"shorter", 3, "WWL"},
+		{"single match", 1, "L"},
+		{"longer than the matches played", 10, "WWDLWWL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := svc.GetTeamStats(team.ID, ReportFilter{}, tt.formLength)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.Form != tt.want {
+				t.Errorf("form over %d = %q, want %q", tt.formLength, stats.Form, tt.want)
+			}
+		})
+	}
+}
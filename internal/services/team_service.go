@@ -7,7 +7,7 @@ import (
 
 type TeamService interface {
 	CreateTeam(team *models.Team) error
-	GetAllTeams() ([]models.Team, error)
+	ListTeams(filter repositories.TeamListFilter, opts repositories.ListOptions) ([]models.Team, int64, error)
 	GetTeamByID(id uint) (*models.Team, error)
 	UpdateTeam(team *models.Team) error
 	DeleteTeam(id uint) error
@@ -25,8 +25,8 @@ func (s *teamService) CreateTeam(team *models.Team) error {
 	return s.repo.Create(team)
 }
 
-func (s *teamService) GetAllTeams() ([]models.Team, error) {
-	return s.repo.FindAll()
+func (s *teamService) ListTeams(filter repositories.TeamListFilter, opts repositories.ListOptions) ([]models.Team, int64, error) {
+	return s.repo.List(filter, opts)
 }
 
 func (s *teamService) GetTeamByID(id uint) (*models.Team, error) {
@@ -13,7 +13,7 @@ func main() {
 	db := database.Connect(cfg)
 	database.Migrate(db)
 
-	r := routers.Setup(db)
+	r := routers.Setup(db, cfg)
 
 	log.Printf("Server running on port %s", cfg.Port)
 	r.Run(":" + cfg.Port)
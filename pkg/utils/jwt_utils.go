@@ -1,45 +1,279 @@
 package utils
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
 	"time"
 
+	"xyz-football/config"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte("supersecretkey") // pindahkan ke .env di production
+// minSecretLength is the shortest HS256 secret accepted, 256 bits.
+const minSecretLength = 32
 
 type JWTClaim struct {
-	UserID uint `json:"user_id"`
+	UserID    uint   `json:"user_id"`
+	Role      string `json:"role"`
+	SessionID uint   `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uint) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+// JWTManager signs tokens with the current key and validates them against
+// every active key, looked up by the kid in the token header. Rotating keys
+// is a matter of signing with a new kid while the old key stays listed in
+// JWT_VERIFY_KEYS until its last tokens have expired.
+type JWTManager struct {
+	method   jwt.SigningMethod
+	kid      string
+	signKey  interface{}
+	keys     map[string]interface{} // verification keys by kid, the signing key's own included
+	ttl      time.Duration
+	issuer   string
+	audience string
+}
+
+func NewJWTManager(cfg *config.Config) (*JWTManager, error) {
+	m := &JWTManager{
+		kid:      cfg.JWTKeyID,
+		keys:     map[string]interface{}{},
+		ttl:      cfg.JWTTTL,
+		issuer:   cfg.JWTIssuer,
+		audience: cfg.JWTAudience,
+	}
+
+	switch cfg.JWTAlgorithm {
+	case "HS256":
+		m.method = jwt.SigningMethodHS256
+		secret, err := hmacSecret(cfg.JWTSecret, cfg.JWTRandomSecret)
+		if err != nil {
+			return nil, err
+		}
+		m.signKey, m.keys[m.kid] = secret, secret
+		for kid, old := range cfg.JWTVerifyKeys {
+			if len(old) < minSecretLength {
+				return nil, fmt.Errorf("JWT_VERIFY_KEYS secret %q must be at least %d characters", kid, minSecretLength)
+			}
+			if err := m.addKey(kid, []byte(old)); err != nil {
+				return nil, err
+			}
+		}
+
+	case "RS256", "EdDSA":
+		m.method = jwt.GetSigningMethod(cfg.JWTAlgorithm)
+		if cfg.JWTPrivateKeyFile == "" {
+			return nil, fmt.Errorf("JWT_PRIVATE_KEY_FILE is required for %s", cfg.JWTAlgorithm)
+		}
+		private, public, err := m.loadPrivateKey(cfg.JWTPrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		m.signKey, m.keys[m.kid] = private, public
+		for kid, file := range cfg.JWTVerifyKeys {
+			public, err := m.loadPublicKey(file)
+			if err != nil {
+				return nil, err
+			}
+			if err := m.addKey(kid, public); err != nil {
+				return nil, err
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q, expected HS256, RS256 or EdDSA", cfg.JWTAlgorithm)
+	}
+
+	return m, nil
+}
+
+// hmacSecret checks the configured secret. Only when asked to, for local
+// development, does a missing secret fall back to a random one, which logs
+// everyone out on every restart and is not shared between instances.
+func hmacSecret(secret string, allowRandom bool) ([]byte, error) {
+	if secret == "" {
+		if !allowRandom {
+			return nil, errors.New("JWT_SECRET is required for HS256 (set JWT_RANDOM_SECRET=true to use a random one in development)")
+		}
+		log.Println("Warning: JWT_SECRET is not set, using a random secret until the next restart")
+		random := make([]byte, minSecretLength)
+		if _, err := rand.Read(random); err != nil {
+			return nil, err
+		}
+		return random, nil
+	}
+	if len(secret) < minSecretLength {
+		return nil, fmt.Errorf("JWT_SECRET must be at least %d characters", minSecretLength)
+	}
+	return []byte(secret), nil
+}
+
+func (m *JWTManager) addKey(kid string, key interface{}) error {
+	if _, ok := m.keys[kid]; ok {
+		return fmt.Errorf("JWT key id %q is used twice", kid)
+	}
+	m.keys[kid] = key
+	return nil
+}
+
+func (m *JWTManager) loadPrivateKey(file string) (interface{}, interface{}, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read JWT private key: %w", err)
+	}
+
+	if m.method == jwt.SigningMethodRS256 {
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid RSA private key in %s: %w", file, err)
+		}
+		return key, &key.PublicKey, nil
+	}
+
+	key, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid Ed25519 private key in %s: %w", file, err)
+	}
+	return key, key.(ed25519.PrivateKey).Public(), nil
+}
+
+func (m *JWTManager) loadPublicKey(file string) (interface{}, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT public key: %w", err)
+	}
+
+	if m.method == jwt.SigningMethodRS256 {
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA public key in %s: %w", file, err)
+		}
+		return key, nil
+	}
+
+	key, err := jwt.ParseEdPublicKeyFromPEM(pem)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Ed25519 public key in %s: %w", file, err)
+	}
+	return key, nil
+}
+
+// TTL is how long an access token is valid.
+func (m *JWTManager) TTL() time.Duration {
+	return m.ttl
+}
+
+func (m *JWTManager) GenerateToken(userID uint, role string, sessionID uint) (string, error) {
+	now := time.Now()
 
 	claims := &JWTClaim{
-		UserID: userID,
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    m.issuer,
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
+	if m.audience != "" {
+		claims.Audience = jwt.ClaimStrings{m.audience}
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	token := jwt.NewWithClaims(m.method, claims)
+	token.Header["kid"] = m.kid
+	return token.SignedString(m.signKey)
 }
 
-func ValidateToken(tokenString string) (*JWTClaim, error) {
+func (m *JWTManager) ValidateToken(tokenString string) (*JWTClaim, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{m.method.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if m.issuer != "" {
+		options = append(options, jwt.WithIssuer(m.issuer))
+	}
+	if m.audience != "" {
+		options = append(options, jwt.WithAudience(m.audience))
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaim{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		key, ok := m.keys[kid]
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		return key, nil
+	}, options...)
 	if err != nil {
 		return nil, err
 	}
 
 	claims, ok := token.Claims.(*JWTClaim)
 	if !ok || !token.Valid {
-		return nil, err
+		return nil, errors.New("invalid token")
 	}
 
 	return claims, nil
 }
+
+// JWK is one public key in a JWKS document (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // Ed25519
+	X   string `json:"x,omitempty"`   // Ed25519 public key
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS lists the public keys tokens can be verified with, the signing key
+// first. There is nothing to publish for HS256, whose keys are secret.
+func (m *JWTManager) JWKS() (JWKS, bool) {
+	if m.method == jwt.SigningMethodHS256 {
+		return JWKS{}, false
+	}
+
+	kids := make([]string, 0, len(m.keys))
+	for kid := range m.keys {
+		if kid != m.kid {
+			kids = append(kids, kid)
+		}
+	}
+	sort.Strings(kids)
+	kids = append([]string{m.kid}, kids...)
+
+	set := JWKS{Keys: make([]JWK, 0, len(kids))}
+	for _, kid := range kids {
+		jwk := JWK{Kid: kid, Use: "sig", Alg: m.method.Alg()}
+		switch key := m.keys[kid].(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(key)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, true
+}
@@ -0,0 +1,288 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"xyz-football/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testSecret    = "a-test-secret-of-at-least-32-characters"
+	retiredSecret = "a-retired-secret-of-32-characters-or-more"
+)
+
+func hs256Config(kid, secret string) *config.Config {
+	return &config.Config{
+		JWTAlgorithm: "HS256",
+		JWTSecret:    secret,
+		JWTKeyID:     kid,
+		JWTTTL:       time.Minute,
+	}
+}
+
+func newTestManager(t *testing.T, cfg *config.Config) *JWTManager {
+	t.Helper()
+	m, err := NewJWTManager(cfg)
+	if err != nil {
+		t.Fatalf("NewJWTManager() error = %v", err)
+	}
+	return m
+}
+
+// writePEM writes a key to a file of its own and returns the path.
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// keyFiles generates a key pair for the algorithm and returns the paths of
+// its private and public key files.
+func keyFiles(t *testing.T, algorithm string) (string, string) {
+	t.Helper()
+
+	var private, public interface{}
+	switch algorithm {
+	case "RS256":
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		private, public = key, &key.PublicKey
+	case "EdDSA":
+		pub, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		private, public = key, pub
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writePEM(t, "PRIVATE KEY", privateDER), writePEM(t, "PUBLIC KEY", publicDER)
+}
+
+func TestNewJWTManagerSecrets(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *config.Config
+		wantErr bool
+	}{
+		{"secret long enough", hs256Config("k1", testSecret), false},
+		{"secret too short", hs256Config("k1", "only-31-characters-long-secret!"), true},
+		{"no secret", hs256Config("k1", ""), true},
+		{"random secret when allowed", func() *config.Config {
+			cfg := hs256Config("k1", "")
+			cfg.JWTRandomSecret = true
+			return cfg
+		}(), false},
+		{"retired secret too short", func() *config.Config {
+			cfg := hs256Config("k2", testSecret)
+			cfg.JWTVerifyKeys = map[string]string{"k1": "short"}
+			return cfg
+		}(), true},
+		{"retired key reusing the current kid", func() *config.Config {
+			cfg := hs256Config("k1", testSecret)
+			cfg.JWTVerifyKeys = map[string]string{"k1": retiredSecret}
+			return cfg
+		}(), true},
+		{"unsupported algorithm", &config.Config{JWTAlgorithm: "HS512", JWTSecret: testSecret}, true},
+		{"RS256 without a private key", &config.Config{JWTAlgorithm: "RS256", JWTKeyID: "k1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJWTManager(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewJWTManager() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJWTManagerValidateToken(t *testing.T) {
+	current := hs256Config("current", testSecret)
+	current.JWTIssuer, current.JWTAudience = "xyz-football", "xyz-football-api"
+	current.JWTVerifyKeys = map[string]string{"retired": retiredSecret}
+	m := newTestManager(t, current)
+
+	// signed builds a token by hand, for the ones the manager would never sign
+	signed := func(method jwt.SigningMethod, kid string, key interface{}, mutate func(*JWTClaim)) string {
+		claims := &JWTClaim{
+			UserID: 1,
+			Role:   "super_admin",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "xyz-football",
+				Audience:  jwt.ClaimStrings{"xyz-football-api"},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+			},
+		}
+		if mutate != nil {
+			mutate(claims)
+		}
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = kid
+		s, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	fromManager := func(cfg *config.Config) string {
+		token, err := newTestManager(t, cfg).GenerateToken(1, "super_admin", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	withClaims := func(kid, secret, issuer, audience string) *config.Config {
+		cfg := hs256Config(kid, secret)
+		cfg.JWTIssuer, cfg.JWTAudience = issuer, audience
+		return cfg
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"current key", fromManager(current), false},
+		{"retired key still listed", fromManager(withClaims("retired", retiredSecret, "xyz-football", "xyz-football-api")), false},
+		{"unknown kid", fromManager(withClaims("unknown", testSecret, "xyz-football", "xyz-football-api")), true},
+		{"known kid, other secret", fromManager(withClaims("current", retiredSecret, "xyz-football", "xyz-football-api")), true},
+		{"no kid", signed(jwt.SigningMethodHS256, "", []byte(testSecret), nil), true},
+		{"other HMAC algorithm", signed(jwt.SigningMethodHS384, "current", []byte(testSecret), nil), true},
+		{"unsigned", signed(jwt.SigningMethodNone, "current", jwt.UnsafeAllowNoneSignatureType, nil), true},
+		{"wrong issuer", fromManager(withClaims("current", testSecret, "someone-else", "xyz-football-api")), true},
+		{"wrong audience", fromManager(withClaims("current", testSecret, "xyz-football", "another-api")), true},
+		{"expired", signed(jwt.SigningMethodHS256, "current", []byte(testSecret), func(c *JWTClaim) {
+			c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+		}), true},
+		{"no expiry", signed(jwt.SigningMethodHS256, "current", []byte(testSecret), func(c *JWTClaim) {
+			c.ExpiresAt = nil
+		}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := m.ValidateToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateToken() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && claims.UserID != 1 {
+				t.Errorf("ValidateToken() user = %d, want 1", claims.UserID)
+			}
+		})
+	}
+}
+
+func TestJWTManagerRefusesAlgorithmSwitch(t *testing.T) {
+	private, public := keyFiles(t, "RS256")
+	m := newTestManager(t, &config.Config{
+		JWTAlgorithm:      "RS256",
+		JWTPrivateKeyFile: private,
+		JWTKeyID:          "rsa",
+		JWTTTL:            time.Minute,
+	})
+
+	// HS256 keyed with the public key, which anyone can fetch from the JWKS
+	publicPEM, err := os.ReadFile(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaim{
+		UserID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	})
+	forged.Header["kid"] = "rsa"
+	token, err := forged.SignedString(publicPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.ValidateToken(token); err == nil {
+		t.Error("an HS256 token was accepted by an RS256 manager")
+	}
+}
+
+func TestJWTManagerJWKS(t *testing.T) {
+	t.Run("HS256 publishes nothing", func(t *testing.T) {
+		if _, ok := newTestManager(t, hs256Config("k1", testSecret)).JWKS(); ok {
+			t.Error("JWKS() published HS256 keys")
+		}
+	})
+
+	tests := []struct {
+		algorithm string
+		kty       string
+	}{
+		{"RS256", "RSA"},
+		{"EdDSA", "OKP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			private, _ := keyFiles(t, tt.algorithm)
+			_, retiredA := keyFiles(t, tt.algorithm)
+			_, retiredC := keyFiles(t, tt.algorithm)
+
+			// The signing kid sorts between the retired ones, so only putting
+			// it first on purpose passes
+			m := newTestManager(t, &config.Config{
+				JWTAlgorithm:      tt.algorithm,
+				JWTPrivateKeyFile: private,
+				JWTKeyID:          "b-current",
+				JWTVerifyKeys:     map[string]string{"a-retired": retiredA, "c-retired": retiredC},
+				JWTTTL:            time.Minute,
+			})
+
+			set, ok := m.JWKS()
+			if !ok {
+				t.Fatal("JWKS() published nothing")
+			}
+			var kids []string
+			for _, key := range set.Keys {
+				kids = append(kids, key.Kid)
+				if key.Kty != tt.kty || key.Alg != tt.algorithm || key.Use != "sig" {
+					t.Errorf("key %s = kty %s alg %s use %s, want kty %s alg %s use sig", key.Kid, key.Kty, key.Alg, key.Use, tt.kty, tt.algorithm)
+				}
+			}
+			want := []string{"b-current", "a-retired", "c-retired"}
+			if len(kids) != len(want) || kids[0] != want[0] || kids[1] != want[1] || kids[2] != want[2] {
+				t.Errorf("JWKS() kids = %v, want %v", kids, want)
+			}
+
+			token, err := m.GenerateToken(1, "super_admin", 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := m.ValidateToken(token); err != nil {
+				t.Errorf("ValidateToken() of its own token error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// letters that carry no combining mark and so survive decomposition
+var foldLetters = strings.NewReplacer(
+	"ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "ł", "l", "đ", "d", "ð", "d", "þ", "th", "ı", "i",
+)
+
+// Fold reduces text to a search key: lower case, accents removed and runs of
+// whitespace collapsed, so "  Atlético  Madrid" becomes "atletico madrid".
+// Keys are compared with plain LIKE, which behaves the same on every database.
+func Fold(text string) string {
+	stripped, _, err := transform.String(
+		transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC),
+		strings.ToLower(text),
+	)
+	if err != nil {
+		stripped = strings.ToLower(text)
+	}
+	return strings.Join(strings.Fields(foldLetters.Replace(stripped)), " ")
+}
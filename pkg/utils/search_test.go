@@ -0,0 +1,33 @@
+package utils
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"plain", "Arsenal", "arsenal"},
+		{"accents", "Atlético", "atletico"},
+		{"whitespace collapsed", "  Atlético \t Madrid\n", "atletico madrid"},
+		{"upper case accents", "ÉDER MILITÃO", "eder militao"},
+		{"cedilla and tilde", "São Gonçalo", "sao goncalo"},
+		{"umlaut", "Müller", "muller"},
+		{"sharp s", "Großkreutz", "grosskreutz"},
+		{"ligatures", "Æsir Cœur", "aesir coeur"},
+		{"stroke letters", "Ødegaard Łukasz Đoković", "odegaard lukasz dokovic"},
+		{"dotless i", "Çalhanoğlu Işık", "calhanoglu isik"},
+		{"thorn and eth", "Þór Guðjohnsen", "thor gudjohnsen"},
+		{"digits and punctuation kept", "Schalke 04-II", "schalke 04-ii"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fold(tt.in); got != tt.want {
+				t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}